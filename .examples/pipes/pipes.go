@@ -21,3 +21,30 @@ func CustomPipes() template.FuncMap {
 		},
 	}
 }
+
+//CustomPipesInfo is optional, it describes the custom pipes in `goflat pipes`
+func CustomPipesInfo() map[string]struct {
+	Description string
+	Examples    []string
+	Deprecated  string
+} {
+	return map[string]struct {
+		Description string
+		Examples    []string
+		Deprecated  string
+	}{
+		"replace": {
+			Description: "Replaces the first occurrence of a value.",
+			Examples:    []string{`{{.Name | replace "-" "_"}}`},
+		},
+		"sanitize": {
+			Description: "Reverses the SECRET value.",
+			Examples:    []string{`{{.Private.Secret | sanitize}}`},
+		},
+	}
+}
+
+//CustomPipesOverrides is optional, it declares the default pipes that are intentionally overridden
+func CustomPipesOverrides() []string {
+	return []string{"replace"}
+}
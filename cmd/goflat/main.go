@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -14,15 +13,58 @@ import (
 )
 
 type args struct {
-	Template string   `short:"t" long:"template" description:"Template Path e.g. /PATH/TO/file.{yml,json}"`
+	Template string   `short:"t" long:"template" description:"Template Path e.g. /PATH/TO/file.{yml,json} or a directory of templates rendered into --output"`
 	Inputs   []string `short:"i" long:"inputs" description:"Path to input files e.g. PATH/TO/privte.go [optional ':' struct name]"`
-	Pipes    string   `short:"p" long:"pipes" description:"User defined pipes e.g. /PATH/TO/pipes.go"`
-	Output   string   `short:"o" long:"output" description:"Output Path"`
+	Partials []string `long:"partials" description:"Shared templates parsed before the template, a repeatable directory or glob e.g. /PATH/TO/partials/"`
+	Delims   string   `long:"delims" description:"Left and right delimiters of the template actions separated by a comma e.g. '[[,]]'"`
+	Pipes    []string `short:"p" long:"pipes" description:"User defined pipes files or directories e.g. /PATH/TO/pipes.go"`
+	Output   string   `short:"o" long:"output" description:"Output Path, the output directory of a directory of templates"`
+	Override string   `long:"pipes-override" default:"warn" choice:"allow" choice:"warn" choice:"error" description:"Policy for user defined pipes overriding the default pipes without declaring it in CustomPipesOverrides"`
+	FileRoot string   `long:"file-root" description:"Directory the file pipes cannot read outside of (default: the template directory)"`
+	AllowEnv []string `long:"allow-env" description:"Environment variables the env pipes can read, a repeatable pattern e.g. CI_*"`
+	Engine   string   `long:"engine" default:"text" choice:"text" choice:"html" description:"Template package executing the template, html escapes the values according to their HTML, attribute, JS or URL context"`
+	Strict   bool     `long:"strict" description:"Fail on missing keys and \"<no value>\" in the output and report the unused input fields"`
+	Now      string   `long:"now" description:"Pin the clock of the date pipes e.g. 2016-03-20T15:04:05Z"`
 	Version  bool     `short:"v" long:"version" description:"Show version"`
+
+	pipes  pipesCommand
+	render renderCommand
+}
+
+type pipesCommand struct {
+	Format string `short:"f" long:"format" default:"text" choice:"text" choice:"json" description:"Output format of the pipes listing"`
+}
+
+//Execute is a no-op, the command only selects the listing mode of main
+func (c *pipesCommand) Execute(args []string) error {
+	return nil
+}
+
+type renderCommand struct {
+	Args struct {
+		Template string `positional-arg-name:"TEMPLATE" description:"Template Path, the same as --template"`
+	} `positional-args:"yes"`
+}
+
+//Execute is a no-op, the command only takes the template as an argument
+func (c *renderCommand) Execute(args []string) error {
+	return nil
 }
 
 func main() {
-	args := parseArgs()
+	args, command := parseArgs()
+	if command == "render" && args.render.Args.Template != "" {
+		args.Template = args.render.Args.Template
+	}
+	header := goflat.Header{}
+	if command != "pipes" {
+		var err error
+		header, err = goflat.ReadHeader(args.Template)
+		checkError(err)
+	}
+	if args.Output == "" {
+		args.Output = header.Output
+	}
 	baseDir, err := tmpDir()
 	if err != nil {
 		checkError(fmt.Errorf("%s:%s", "cannot create temp directory", err.Error()))
@@ -30,9 +72,39 @@ func main() {
 	defer os.RemoveAll(baseDir)
 
 	builder, err := goflat.NewFlatBuilder(baseDir, args.Template)
-	err = builder.EvalGoInputs(args.Inputs)
 	checkError(err)
-	err = builder.EvalGoPipes(args.Pipes)
+	if command == "pipes" {
+		err = builder.EvalListPipes(args.pipes.Format)
+		checkError(err)
+	}
+	err = builder.EvalGoInputs(append(header.Inputs, args.Inputs...))
+	checkError(err)
+	err = builder.EvalPartials(args.Partials)
+	checkError(err)
+	err = builder.EvalDelims(args.Delims)
+	checkError(err)
+	err = builder.EvalGoPipes(append(header.Pipes, args.Pipes...))
+	checkError(err)
+	err = builder.EvalPipesOverride(args.Override)
+	checkError(err)
+	err = builder.EvalFileRoot(args.FileRoot)
+	checkError(err)
+	err = builder.EvalAllowEnv(args.AllowEnv)
+	checkError(err)
+	err = builder.EvalEngine(args.Engine)
+	checkError(err)
+	err = builder.EvalFormat(header.Format)
+	checkError(err)
+	if fi, err := os.Stat(args.Template); err == nil && fi.IsDir() {
+		if args.Output == "" {
+			checkError(fmt.Errorf("%s:%s", "rendering a template directory needs --output", args.Template))
+		}
+		err = builder.EvalOutputDir(args.Output)
+		checkError(err)
+	}
+	err = builder.EvalStrict(args.Strict)
+	checkError(err)
+	err = builder.EvalNow(args.Now)
 	checkError(err)
 	err = builder.EvalMainGo()
 	checkError(err)
@@ -41,26 +113,38 @@ func main() {
 
 	var outBuf bytes.Buffer
 	var errBuf bytes.Buffer
-	err = flat.GoRun(bufio.NewWriter(&outBuf), bufio.NewWriter(&errBuf))
+	err = flat.GoRun(&outBuf, &errBuf)
 	if err != nil {
 		checkError(errors.New(fmt.Sprintf("%s:%s:%s", err.Error(), errBuf.String(), outBuf.String())))
 	}
-	if args.Output == "" {
+	fmt.Fprint(os.Stderr, errBuf.String())
+	if flat.OutputDir != "" {
+		fmt.Print(outBuf.String())
+	} else if args.Output == "" {
 		fmt.Println(outBuf.String())
 	} else {
-		err := ioutil.WriteFile(args.Output, outBuf.Bytes(), 0640)
+		err := os.MkdirAll(filepath.Dir(args.Output), 0777)
+		checkError(err)
+		err = ioutil.WriteFile(args.Output, outBuf.Bytes(), 0640)
 		checkError(err)
 	}
 }
 
-func parseArgs() *args {
+func parseArgs() (*args, string) {
 	if len(os.Args) <= 1 {
 		fmt.Println("Run --help for more help")
 		os.Exit(1)
 	}
 	var args args
 	parser := flags.NewParser(&args, flags.HelpFlag|flags.PrintErrors|flags.PassDoubleDash)
-	_, err := parser.Parse()
+	parser.SubcommandsOptional = true
+	_, err := parser.AddCommand("pipes", "List the available pipes",
+		"List the default pipes and the user defined pipes of --pipes with their signature and description", &args.pipes)
+	checkError(err)
+	_, err = parser.AddCommand("render", "Render a template",
+		"Render a template with the inputs, pipes, output, delims and format declared by its header and the options", &args.render)
+	checkError(err)
+	_, err = parser.Parse()
 	if err != nil {
 		os.Exit(0)
 	}
@@ -69,7 +153,10 @@ func parseArgs() *args {
 		fmt.Println(goflat.Version + goflat.VersionPrerelease)
 		os.Exit(0)
 	}
-	return &args
+	if parser.Active != nil {
+		return &args, parser.Active.Name
+	}
+	return &args, ""
 }
 
 func tmpDir() (string, error) {
@@ -4,11 +4,20 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,11 +26,37 @@ import (
 
 //Flat struct
 type Flat struct {
-	MainGo       string
-	GoTemplate   string
-	GoInputs     []goInput
-	DefaultPipes string
-	CustomPipes  string
+	MainGo          string
+	GoTemplate      string
+	GoInputs        []goInput
+	DefaultPipes    []string
+	CustomPipes     []string
+	CustomProviders []goProvider
+	//CustomPipesInfo are the functions describing the custom pipes
+	CustomPipesInfo []string
+	//CustomPipesOverrides are the functions declaring the default pipes the custom pipes override
+	CustomPipesOverrides []string
+	PipesOverride        string
+	Now                  string
+	ListPipes            string
+	//FileDir and FileRoot are the absolute directories of the relative paths and the sandbox of the file pipes
+	FileDir  string
+	FileRoot string
+	AllowEnv []string
+	//Partials are the files parsed into the template set before the template
+	Partials []string
+	//LeftDelim and RightDelim are the delimiters of the template actions, empty is "{{" and "}}"
+	LeftDelim  string
+	RightDelim string
+	//Strict fails on missing keys and "<no value>" in the output and reports the unused input fields
+	Strict bool
+	//Engine is "text" or "html" for executing the template with html/template
+	Engine string
+	//Format is the format of the output, "json" and "xml" outputs are checked
+	Format string
+	//OutputDir is the directory the Files of a template directory are rendered into
+	OutputDir string
+	Files     []goFile
 
 	goPath string
 	cmdEnv []string
@@ -38,11 +73,10 @@ func (f *Flat) GoRun(outWriter io.Writer, errWriter io.Writer) error {
 	if err != nil {
 		return err
 	}
-	out := []string{"run", f.MainGo, f.DefaultPipes}
+	out := []string{"run", f.MainGo}
+	out = append(out, f.DefaultPipes...)
 
-	if f.CustomPipes != "" {
-		out = append(out, f.CustomPipes)
-	}
+	out = append(out, f.CustomPipes...)
 	for _, v := range f.GoInputs {
 		out = append(out, v.Path)
 	}
@@ -86,9 +120,12 @@ func (f *Flat) validate() error {
 	if f.MainGo == "" {
 		msgs = append(msgs, ErrMainGoUndefined)
 	}
-	if f.DefaultPipes == "" {
+	if len(f.DefaultPipes) == 0 {
 		msgs = append(msgs, ErrDefaultPipesUndefined)
 	}
+	if f.GoTemplate == "" && f.ListPipes == "" {
+		msgs = append(msgs, ErrTemplateUndefined)
+	}
 
 	if len(msgs) > 0 {
 		return errors.New(strings.Join(msgs, ","))
@@ -118,6 +155,216 @@ func newGoInput(input string) goInput {
 	}
 }
 
+//goProvider is a function of the custom pipes returning a template.FuncMap, Name is reported as the source of its pipes
+type goProvider struct{ Name, Func string }
+
+//goPipesPackage is a custom pipes file or directory as a part of the main package, the package level identifiers
+//of its files are prefixed so that they do not collide with the other pipes packages or the runtime
+type goPipesPackage struct {
+	Src       [][]byte
+	Providers []goProvider
+	//Info and Overrides are the renamed CustomPipesInfo and CustomPipesOverrides functions
+	Info      []string
+	Overrides []string
+}
+
+//newGoPipesPackage finds the provider functions of the go files of a package in declaration order, renames
+//the package to main and prefixes its package level identifiers
+func newGoPipesPackage(files []string, prefix string) (goPipesPackage, error) {
+	fset := token.NewFileSet()
+	srcs := make([][]byte, len(files))
+	asts := make([]*ast.File, len(files))
+	gp := goPipesPackage{}
+	for k, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return goPipesPackage{}, err
+		}
+		f, err := parser.ParseFile(fset, file, src, 0)
+		if err != nil {
+			return goPipesPackage{}, err
+		}
+		srcs[k], asts[k] = src, f
+		imports := make(map[string]string)
+		for _, v := range f.Imports {
+			p, _ := strconv.Unquote(v.Path.Value)
+			name := path.Base(p)
+			if v.Name != nil {
+				name = v.Name.Name
+			}
+			imports[name] = p
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			switch {
+			case fn.Name.Name == "CustomPipesInfo":
+				gp.Info = append(gp.Info, prefix+fn.Name.Name)
+			case fn.Name.Name == "CustomPipesOverrides":
+				gp.Overrides = append(gp.Overrides, prefix+fn.Name.Name)
+			case isFuncMapProvider(fn.Type, imports):
+				gp.Providers = append(gp.Providers, goProvider{Name: file + ":" + fn.Name.Name, Func: prefix + fn.Name.Name})
+			}
+		}
+	}
+
+	gp.Src = renamePackage(fset, asts, srcs, prefix)
+	return gp, nil
+}
+
+//renamePackage renames the package of the parsed files to main and prefixes their package level identifiers
+//except init and the blank identifier
+func renamePackage(fset *token.FileSet, asts []*ast.File, srcs [][]byte, prefix string) [][]byte {
+	//the imports are not needed for resolving the package level identifiers, their errors are ignored
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object), Uses: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{Importer: noImporter{}, Error: func(error) {}}
+	pkg, _ := conf.Check(asts[0].Name.Name, fset, asts, info)
+	edits := make([][]goPipesEdit, len(asts))
+	index := make(map[*token.File]int, len(asts))
+	for k, f := range asts {
+		index[fset.File(f.Pos())] = k
+		pos := fset.Position(f.Name.Pos())
+		edits[k] = append(edits[k], goPipesEdit{pos.Offset, len(f.Name.Name), "main"})
+	}
+	rename := func(ident *ast.Ident, obj types.Object) {
+		if obj == nil || obj.Pkg() != pkg || ident.Name == "_" || ident.Name == "init" ||
+			pkg.Scope().Lookup(ident.Name) != obj {
+			return
+		}
+		pos := fset.Position(ident.Pos())
+		k := index[fset.File(ident.Pos())]
+		edits[k] = append(edits[k], goPipesEdit{pos.Offset, len(ident.Name), prefix + ident.Name})
+	}
+	for ident, obj := range info.Defs {
+		rename(ident, obj)
+	}
+	for ident, obj := range info.Uses {
+		rename(ident, obj)
+	}
+	out := make([][]byte, len(srcs))
+	for k, src := range srcs {
+		sort.Slice(edits[k], func(i, j int) bool { return edits[k][i].offset > edits[k][j].offset })
+		out[k] = append([]byte{}, src...)
+		for _, e := range edits[k] {
+			out[k] = append(append(append([]byte{}, out[k][:e.offset]...), e.text...), out[k][e.offset+e.length:]...)
+		}
+	}
+	return out
+}
+
+//goPipesEdit replaces length bytes at offset of a pipes file with text
+type goPipesEdit struct {
+	offset, length int
+	text           string
+}
+
+//noImporter does not import the packages of the custom pipes
+type noImporter struct{}
+
+func (noImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("%s is not imported", path)
+}
+
+//isFuncMapProvider reports whether a function takes no arguments and returns a text/template FuncMap
+func isFuncMapProvider(ft *ast.FuncType, imports map[string]string) bool {
+	if ft.Params.NumFields() != 0 || ft.Results.NumFields() != 1 {
+		return false
+	}
+	sel, ok := ft.Results.List[0].Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "FuncMap" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && imports[x.Name] == "text/template"
+}
+
+//goPipesFiles expands the custom pipes files and directories into the go files of their packages,
+//the files of a directory are sorted by name without the tests and a file is only part of its first package
+func goPipesFiles(paths []string) ([][]string, error) {
+	out := [][]string{}
+	seen := make(map[string]bool)
+	for _, v := range paths {
+		fi, err := os.Stat(v)
+		if err != nil {
+			return nil, err
+		}
+		files := []string{v}
+		if fi.IsDir() {
+			files, err = filepath.Glob(filepath.Join(v, "*.go"))
+			if err != nil {
+				return nil, err
+			}
+			sort.Strings(files)
+		}
+		pkg := []string{}
+		for _, f := range files {
+			if fi.IsDir() && strings.HasSuffix(f, "_test.go") {
+				continue
+			}
+			if abs, _ := filepath.Abs(f); !seen[abs] {
+				seen[abs] = true
+				pkg = append(pkg, f)
+			}
+		}
+		if len(pkg) > 0 {
+			out = append(out, pkg)
+		}
+	}
+	return out, nil
+}
+
+//delimsHeader is a comment at the beginning of a template written in the delimiters it declares e.g. [[/* delims "[[" "]]" */]]
+var delimsHeader = regexp.MustCompile(`^\s*\S*?\s*/\*\s*delims\s+"([^"]+)"\s+"([^"]+)"\s*\*/`)
+
+//templateDelims returns the delimiters declared by the header of a template
+func templateDelims(text string) (string, string, bool) {
+	m := delimsHeader.FindStringSubmatch(text)
+	if m == nil {
+		return "", "", false
+	}
+	left, right := m[1], m[2]
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(left) + `-?\s*/\*\s*delims\s+"[^"]+"\s+"[^"]+"\s*\*/\s*-?` + regexp.QuoteMeta(right))
+	if !re.MatchString(text) {
+		return "", "", false
+	}
+	return left, right, true
+}
+
+//goFile is a file of a template directory, Name is its templatable output path and a Copy is not a template,
+//the empty options of a template are the options of the directory
+type goFile struct {
+	Path, Name                            string
+	Copy                                  bool
+	LeftDelim, RightDelim, Engine, Format string
+}
+
+//treeFiles returns the files of a template directory in lexical order without the names starting with "_"
+//and without the output directory
+func treeFiles(root, outputDir string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if abs, _ := filepath.Abs(path); abs == outputDir {
+			return filepath.SkipDir
+		}
+		if path != root && strings.HasPrefix(fi.Name(), "_") {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
 // environ is a slice of strings representing the environment, in the form "key=value".
 type environ []string
 
@@ -135,6 +382,7 @@ func (e *environ) Unset(key string) {
 const (
 	ErrMainGoUndefined       = "(main func is missing)"
 	ErrDefaultPipesUndefined = "(default pipes file is missing)"
+	ErrTemplateUndefined     = "(template file is missing)"
 )
 
 func init() {
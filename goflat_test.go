@@ -58,7 +58,7 @@ var _ = Describe("GoFlat", func() {
 			Expect(err.Error()).To(ContainSubstring(ErrDefaultPipesUndefined))
 		})
 		It("should catch undefined MainGo", func() {
-			builder.EvalGoPipes("")
+			builder.EvalGoPipes(nil)
 			flat := builder.Flat()
 			err := flat.GoRun(writer, writer)
 			Expect(err).ToNot(BeNil())
@@ -87,7 +87,7 @@ var _ = Describe("GoFlat", func() {
 			builder, err := NewFlatBuilder(tmpDir, template)
 			Expect(err).To(BeNil())
 
-			err = builder.EvalGoPipes(customPipes)
+			err = builder.EvalGoPipes([]string{customPipes})
 			Expect(err).To(BeNil())
 			err = builder.EvalMainGo()
 			Expect(err).To(BeNil())
@@ -124,7 +124,7 @@ var _ = Describe("GoFlat", func() {
 			builder, err := NewFlatBuilder(tmpDir, template)
 			Expect(err).To(BeNil())
 
-			err = builder.EvalGoPipes("")
+			err = builder.EvalGoPipes(nil)
 			Expect(err).To(BeNil())
 			err = builder.EvalMainGo()
 			Expect(err).To(BeNil())
@@ -162,7 +162,7 @@ var _ = Describe("GoFlat", func() {
 
 			err = builder.EvalGoInputs([]string{inputFile})
 			Expect(err).To(BeNil())
-			err = builder.EvalGoPipes("")
+			err = builder.EvalGoPipes(nil)
 			Expect(err).To(BeNil())
 			err = builder.EvalMainGo()
 			Expect(err).To(BeNil())
@@ -200,7 +200,7 @@ var _ = Describe("GoFlat", func() {
 			}
 			err = builder.EvalGoInputs(inputFiles)
 			Expect(err).To(BeNil())
-			err = builder.EvalGoPipes("")
+			err = builder.EvalGoPipes(nil)
 			Expect(err).To(BeNil())
 			err = builder.EvalMainGo()
 			Expect(err).To(BeNil())
@@ -2,19 +2,36 @@ package goflat
 
 import (
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 )
 
 //Builder pattern seems to be the most appropriate structure for building a `Flat` struct
 type FlatBuilder interface {
 	EvalGoInputs(files []string) error
-	EvalGoPipes(file string) error
+	EvalGoPipes(files []string) error
+	EvalNow(now string) error
+	EvalListPipes(format string) error
+	EvalPipesOverride(policy string) error
+	EvalFileRoot(root string) error
+	EvalAllowEnv(patterns []string) error
+	EvalPartials(paths []string) error
+	EvalDelims(delims string) error
+	EvalStrict(strict bool) error
+	EvalEngine(engine string) error
+	EvalFormat(format string) error
+	EvalOutputDir(dir string) error
 	EvalMainGo() error
 	Flat() *Flat
 }
@@ -44,34 +61,303 @@ func (builder *flatBuilder) cp(file string) (string, error) {
 	return outFile, cerr
 }
 
+//EvalGoInputs copies the input files, a file is only added once e.g. when a header and "-i" both name it
 func (builder *flatBuilder) EvalGoInputs(files []string) error {
-	builder.flat.GoInputs = make([]goInput, len(files))
-	for k, v := range files {
+	builder.flat.GoInputs = make([]goInput, 0, len(files))
+	seen := make(map[string]bool)
+	for _, v := range files {
 		gi := newGoInput(v)
+		abs, err := filepath.Abs(gi.Path)
+		if err != nil {
+			return err
+		}
+		if seen[abs] {
+			continue
+		}
+		seen[abs] = true
 		file, err := builder.cp(gi.Path)
 		if err != nil {
 			return fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
 		}
 		gi.Path = file
-		builder.flat.GoInputs[k] = gi
+		builder.flat.GoInputs = append(builder.flat.GoInputs, gi)
 	}
 	return nil
 }
 
-func (builder *flatBuilder) EvalGoPipes(file string) error {
+//EvalGoPipes adds the custom pipes of go files and directories, every function returning a template.FuncMap
+//is a provider and the providers are merged in the order of the files and their declarations
+func (builder *flatBuilder) EvalGoPipes(files []string) error {
 	defaultPipes, err := builder.defaultPipes()
 	if err != nil {
 		return err
 	}
 	builder.flat.DefaultPipes = defaultPipes
+	if len(files) == 0 {
+		return nil
+	}
+
+	pipesFiles, err := goPipesFiles(files)
+	if err != nil {
+		return fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	}
+	for k, v := range pipesFiles {
+		gp, err := newGoPipesPackage(v, fmt.Sprintf("goflat%d_", k+1))
+		if err != nil {
+			return fmt.Errorf("%s:%s", ErrInvalidPipes, err.Error())
+		}
+		for _, src := range gp.Src {
+			outFile := filepath.Join(builder.baseDir, nameGenerator())
+			if err := ioutil.WriteFile(outFile, src, 0666); err != nil {
+				return err
+			}
+			builder.flat.CustomPipes = append(builder.flat.CustomPipes, outFile)
+		}
+		builder.flat.CustomProviders = append(builder.flat.CustomProviders, gp.Providers...)
+		builder.flat.CustomPipesInfo = append(builder.flat.CustomPipesInfo, gp.Info...)
+		builder.flat.CustomPipesOverrides = append(builder.flat.CustomPipesOverrides, gp.Overrides...)
+	}
+	if len(builder.flat.CustomProviders) == 0 {
+		return fmt.Errorf("%s:%s", ErrPipesUndefined, strings.Join(files, ","))
+	}
+	return nil
+}
+
+//EvalNow pins the clock of the date pipes to a RFC3339 timestamp, an empty value uses the current time
+func (builder *flatBuilder) EvalNow(now string) error {
+	if now == "" {
+		builder.flat.Now = ""
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, now)
+	if err != nil {
+		return fmt.Errorf("%s:%s", ErrInvalidNow, err.Error())
+	}
+	builder.flat.Now = t.Format(time.RFC3339Nano)
+	return nil
+}
+
+//EvalListPipes makes the program list the available pipes in "text" or "json" format instead of running the template
+func (builder *flatBuilder) EvalListPipes(format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("%s:%s", ErrInvalidFormat, format)
+	}
+	builder.flat.ListPipes = format
+	return nil
+}
+
+//EvalPipesOverride sets the policy for custom pipes overriding the default pipes: "allow", "warn" or "error",
+//an empty value keeps the default "warn"
+func (builder *flatBuilder) EvalPipesOverride(policy string) error {
+	switch policy {
+	case "":
+		builder.flat.PipesOverride = DefaultPipesOverride
+	case "allow", "warn", "error":
+		builder.flat.PipesOverride = policy
+	default:
+		return fmt.Errorf("%s:%s", ErrInvalidOverride, policy)
+	}
+	return nil
+}
+
+//EvalFileRoot sets the directory the file pipes cannot read outside of, an empty value is the template directory
+func (builder *flatBuilder) EvalFileRoot(root string) error {
+	if root == "" {
+		builder.flat.FileRoot = builder.flat.FileDir
+		return nil
+	}
+	fi, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s:%s", ErrNotDirectory, root)
+	}
+	builder.flat.FileRoot, err = filepath.Abs(root)
+	return err
+}
+
+//EvalAllowEnv sets the patterns of the environment variables the env pipes can read e.g. "CI_*"
+func (builder *flatBuilder) EvalAllowEnv(patterns []string) error {
+	for _, v := range patterns {
+		if _, err := path.Match(v, ""); err != nil {
+			return fmt.Errorf("%s:%s", ErrInvalidEnvPattern, v)
+		}
+	}
+	builder.flat.AllowEnv = patterns
+	return nil
+}
 
-	if file != "" {
-		customPipes, err := builder.cp(file)
+//EvalPartials adds the files of directories and glob patterns to the template set,
+//the files of a directory are sorted by name without the hidden files
+func (builder *flatBuilder) EvalPartials(paths []string) error {
+	builder.flat.Partials = []string{}
+	for _, v := range paths {
+		pattern := v
+		if fi, err := os.Stat(v); err == nil && fi.IsDir() {
+			pattern = filepath.Join(v, "*")
+		}
+		matches, err := filepath.Glob(pattern)
 		if err != nil {
 			return fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
 		}
-		builder.flat.CustomPipes = customPipes
+		if len(matches) == 0 {
+			return fmt.Errorf("%s:%s", ErrMissingOnDisk, v)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err != nil || fi.IsDir() || strings.HasPrefix(filepath.Base(m), ".") {
+				continue
+			}
+			abs, err := filepath.Abs(m)
+			if err != nil {
+				return err
+			}
+			builder.flat.Partials = append(builder.flat.Partials, abs)
+		}
+	}
+	return nil
+}
+
+//EvalDelims sets the left and right delimiters of the template actions separated by a comma e.g. "[[,]]",
+//a template can declare them with a delims header or its front-matter instead and an empty value without a
+//header keeps "{{" and "}}". Delimiters other than the ones the template declares are an error
+func (builder *flatBuilder) EvalDelims(delims string) error {
+	builder.flat.LeftDelim, builder.flat.RightDelim = "", ""
+	left, right, err := splitDelims(delims)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(builder.flat.GoTemplate); err == nil && !fi.IsDir() {
+		headerLeft, headerRight, err := headerDelims(builder.flat.GoTemplate)
+		if err != nil {
+			return err
+		}
+		if headerLeft != "" {
+			if left != "" && (left != headerLeft || right != headerRight) {
+				return fmt.Errorf("%s:%s declares %s,%s", ErrConflictingDelims, builder.flat.GoTemplate, headerLeft, headerRight)
+			}
+			left, right = headerLeft, headerRight
+		}
 	}
+	builder.flat.LeftDelim, builder.flat.RightDelim = left, right
+	return nil
+}
+
+//headerDelims returns the delimiters declared by a delims header or the front-matter of a template
+func headerDelims(template string) (string, string, error) {
+	data, err := ioutil.ReadFile(template)
+	if err != nil {
+		return "", "", fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	}
+	if left, right, ok := templateDelims(string(data)); ok {
+		return left, right, nil
+	}
+	h, err := parseHeader(string(data))
+	if err != nil {
+		return "", "", fmt.Errorf("%s:%s", ErrInvalidHeader, err.Error())
+	}
+	return splitDelims(h.Delims)
+}
+
+func splitDelims(delims string) (string, string, error) {
+	if delims == "" {
+		return "", "", nil
+	}
+	d := strings.Split(delims, ",")
+	if len(d) != 2 || strings.TrimSpace(d[0]) == "" || strings.TrimSpace(d[1]) == "" {
+		return "", "", fmt.Errorf("%s:%s", ErrInvalidDelims, delims)
+	}
+	return strings.TrimSpace(d[0]), strings.TrimSpace(d[1]), nil
+}
+
+//EvalStrict makes the program fail on missing keys and "<no value>" in the output and report the unused input fields
+func (builder *flatBuilder) EvalStrict(strict bool) error {
+	builder.flat.Strict = strict
+	return nil
+}
+
+//EvalEngine sets the template package executing the template: "text" or "html" for contextual escaping,
+//an empty value keeps the default "text"
+func (builder *flatBuilder) EvalEngine(engine string) error {
+	switch engine {
+	case "":
+		builder.flat.Engine = DefaultEngine
+	case "text", "html":
+		builder.flat.Engine = engine
+	default:
+		return fmt.Errorf("%s:%s", ErrInvalidEngine, engine)
+	}
+	return nil
+}
+
+//EvalFormat sets the format of the output: "json" and "xml" outputs must be well-formed, "html" executes the template
+//with html/template and an empty value keeps the default "text"
+func (builder *flatBuilder) EvalFormat(format string) error {
+	switch format {
+	case "":
+		builder.flat.Format = DefaultFormat
+	case "html":
+		builder.flat.Format, builder.flat.Engine = format, "html"
+	case "text", "json", "xml":
+		builder.flat.Format = format
+	default:
+		return fmt.Errorf("%s:%s", ErrInvalidOutputFormat, format)
+	}
+	return nil
+}
+
+//EvalOutputDir renders a directory of templates into dir with the same structure, the files ending with ".tmpl"
+//are rendered without the extension, the other files are copied and the names starting with "_" are skipped.
+//The delims and the format of the header of a template apply to it and its name is a template, delims other than
+//the ones set by EvalDelims are an error
+func (builder *flatBuilder) EvalOutputDir(dir string) error {
+	if fi, err := os.Stat(builder.flat.GoTemplate); err != nil || !fi.IsDir() {
+		return fmt.Errorf("%s:%s", ErrNotDirectory, builder.flat.GoTemplate)
+	}
+	outputDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	files, err := treeFiles(builder.flat.GoTemplate, outputDir)
+	if err != nil {
+		return fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	}
+	builder.flat.Files = []goFile{}
+	for _, v := range files {
+		rel, err := filepath.Rel(builder.flat.GoTemplate, v)
+		if err != nil {
+			return err
+		}
+		path, err := filepath.Abs(v)
+		if err != nil {
+			return err
+		}
+		f := goFile{Path: path, Name: filepath.ToSlash(rel), Copy: !strings.HasSuffix(rel, ".tmpl")}
+		if !f.Copy {
+			f.Name = strings.TrimSuffix(f.Name, ".tmpl")
+			if f.LeftDelim, f.RightDelim, err = headerDelims(v); err != nil {
+				return err
+			}
+			if f.LeftDelim != "" && builder.flat.LeftDelim != "" && (f.LeftDelim != builder.flat.LeftDelim || f.RightDelim != builder.flat.RightDelim) {
+				return fmt.Errorf("%s:%s declares %s,%s", ErrConflictingDelims, v, f.LeftDelim, f.RightDelim)
+			}
+			h, err := ReadHeader(v)
+			if err != nil {
+				return err
+			}
+			switch h.Format {
+			case "", "text", "json", "xml":
+				f.Format = h.Format
+			case "html":
+				f.Format, f.Engine = h.Format, "html"
+			default:
+				return fmt.Errorf("%s:%s:%s", ErrInvalidOutputFormat, v, h.Format)
+			}
+		}
+		builder.flat.Files = append(builder.flat.Files, f)
+	}
+	builder.flat.OutputDir = outputDir
 	return nil
 }
 
@@ -96,12 +382,15 @@ func (builder *flatBuilder) Flat() *Flat {
 }
 
 //NewFlatBuilder initializes a new instance of `FlatBuilder` interface
+//template can be empty when only listing the pipes
 func NewFlatBuilder(baseDir, template string) (FlatBuilder, error) {
 	if _, err := os.Stat(baseDir); err != nil {
 		return nil, fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
 	}
-	if _, err := os.Stat(template); err != nil {
-		return nil, fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	if template != "" {
+		if _, err := os.Stat(template); err != nil {
+			return nil, fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+		}
 	}
 
 	src_dir := filepath.Join(baseDir, "src")
@@ -109,25 +398,49 @@ func NewFlatBuilder(baseDir, template string) (FlatBuilder, error) {
 
 	goflatDir, _ := ioutil.TempDir(src_dir, "goflat")
 	goPath, _ := filepath.Abs(baseDir)
+	fileDir, _ := filepath.Abs(filepath.Dir(template))
+	if fi, err := os.Stat(template); err == nil && fi.IsDir() {
+		fileDir, _ = filepath.Abs(template)
+	}
 	builder := &flatBuilder{
 		baseDir: goflatDir,
 		flat: &Flat{
-			GoTemplate: template,
-			goPath:     goPath,
+			GoTemplate:    template,
+			PipesOverride: DefaultPipesOverride,
+			Engine:        DefaultEngine,
+			Format:        DefaultFormat,
+			FileDir:       fileDir,
+			FileRoot:      fileDir,
+			goPath:        goPath,
 		},
 	}
 
 	return builder, nil
 }
 
-func (builder *flatBuilder) defaultPipes() (string, error) {
-	content := strings.Replace(PipesGo, "package runtime", "package main", -1)
-	outFile := filepath.Join(builder.baseDir, nameGenerator())
-	err := ioutil.WriteFile(outFile, []byte(content), 0666)
-	if err != nil {
-		return "", err
+//defaultPipes writes the runtime files as a part of the main package, their package level identifiers have the
+//RuntimePrefix so that they do not collide with the inputs
+func (builder *flatBuilder) defaultPipes() ([]string, error) {
+	fset := token.NewFileSet()
+	asts := make([]*ast.File, len(RuntimePipes))
+	srcs := make([][]byte, len(RuntimePipes))
+	for k, v := range RuntimePipes {
+		f, err := parser.ParseFile(fset, fmt.Sprintf("runtime%d.go", k), v, 0)
+		if err != nil {
+			return nil, err
+		}
+		asts[k], srcs[k] = f, []byte(v)
 	}
-	return outFile, nil
+	files := make([]string, len(RuntimePipes))
+	for k, v := range renamePackage(fset, asts, srcs, RuntimePrefix) {
+		outFile := filepath.Join(builder.baseDir, nameGenerator())
+		err := ioutil.WriteFile(outFile, v, 0666)
+		if err != nil {
+			return nil, err
+		}
+		files[k] = outFile
+	}
+	return files, nil
 }
 
 func nameGenerator() string {
@@ -141,7 +454,41 @@ func nameGenerator() string {
 	return string(buf) + ".go"
 }
 
+//RuntimePrefix prefixes the package level identifiers of the runtime in the main package e.g. goflat_NewPipes
+const RuntimePrefix = "goflat_"
+
+//DefaultPipesOverride reports the custom pipes that override a default pipe without declaring it
+const DefaultPipesOverride = "warn"
+
+//DefaultEngine executes the template with text/template
+const DefaultEngine = "text"
+
+//DefaultFormat does not check the output
+const DefaultFormat = "text"
+
 const (
 	//ErrMissingOnDisk Expected error for accessing invalid file or directory
 	ErrMissingOnDisk = "(file or directory is missing)"
+	//ErrInvalidNow Expected error for a clock that is not a RFC3339 timestamp
+	ErrInvalidNow = "(now is not a RFC3339 timestamp)"
+	//ErrInvalidPipes Expected error for a pipes file that is not valid go
+	ErrInvalidPipes = "(pipes file cannot be parsed)"
+	//ErrPipesUndefined Expected error for custom pipes without a function returning a template.FuncMap
+	ErrPipesUndefined = "(no function returning template.FuncMap in pipes)"
+	//ErrInvalidFormat Expected error for listing the pipes in an unknown format
+	ErrInvalidFormat = "(format is not text or json)"
+	//ErrNotDirectory Expected error for a file where a directory is needed
+	ErrNotDirectory = "(path is not a directory)"
+	//ErrInvalidEnvPattern Expected error for a malformed environment variable pattern
+	ErrInvalidEnvPattern = "(environment variable pattern is malformed)"
+	//ErrInvalidOverride Expected error for an unknown pipes override policy
+	ErrInvalidOverride = "(pipes override is not allow, warn or error)"
+	//ErrInvalidDelims Expected error for delimiters that are not a left and a right delimiter separated by a comma
+	ErrInvalidDelims = "(delims are not left and right delimiters separated by a comma)"
+	//ErrConflictingDelims Expected error for delims other than the ones declared by the template
+	ErrConflictingDelims = "(delims are not the ones declared by the template)"
+	//ErrInvalidEngine Expected error for an unknown template engine
+	ErrInvalidEngine = "(engine is not text or html)"
+	//ErrInvalidOutputFormat Expected error for an unknown output format
+	ErrInvalidOutputFormat = "(output format is not text, json, xml or html)"
 )
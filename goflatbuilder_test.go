@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 
 	. "github.com/aminjam/goflat"
@@ -25,6 +26,15 @@ var _ = Describe("GoFlatBuilder", func() {
 	AfterEach(func() {
 		defer os.RemoveAll(tmpDir)
 	})
+	//goBuild compiles the program of a flat without running it
+	goBuild := func(flat *Flat) ([]byte, error) {
+		args := append([]string{"build", "-o", filepath.Join(tmpDir, "goflat")}, flat.MainGo)
+		args = append(append(args, flat.DefaultPipes...), flat.CustomPipes...)
+		for _, v := range flat.GoInputs {
+			args = append(args, v.Path)
+		}
+		return exec.Command("go", args...).CombinedOutput()
+	}
 
 	Context("with invalid params", func() {
 		It("should catch invalid baseDir", func() {
@@ -76,6 +86,40 @@ var _ = Describe("GoFlatBuilder", func() {
 			orgFileInfo, _ := os.Stat(inputFiles[0])
 			Expect(orgFileInfo.Size()).To(Equal(newFileInfo.Size()))
 		})
+		It("should add an input named by a header and a flag once", func() {
+			input := filepath.Join(examples, "inputs", "private.go")
+			err := builder.EvalGoInputs([]string{input, examples + "/inputs/../inputs/private.go"})
+			Expect(err).To(BeNil())
+			flat := builder.Flat()
+			Expect(len(flat.GoInputs)).To(Equal(1))
+			err = builder.EvalGoPipes(nil)
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			out, err := goBuild(builder.Flat())
+			Expect(err).To(BeNil(), string(out))
+		})
+		It("should compile inputs declaring the names of the runtime", func() {
+			inputDir, _ := ioutil.TempDir(os.TempDir(), "")
+			defer os.RemoveAll(inputDir)
+			input := filepath.Join(inputDir, "template.go")
+			err := ioutil.WriteFile(input, []byte(`package main
+type Template struct{ Name string }
+func NewTemplate() Template { return Template{Name: sign(1) + toString(2)} }
+func sign(i int) string { return "+" }
+func toString(i int) string { return "2" }
+func indirect() {}
+func NewPipes() {}`), 0666)
+			Expect(err).To(BeNil())
+			err = builder.EvalGoInputs([]string{input})
+			Expect(err).To(BeNil())
+			err = builder.EvalGoPipes(nil)
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			out, err := goBuild(builder.Flat())
+			Expect(err).To(BeNil(), string(out))
+		})
 		It("should evaluate files with custom struct", func() {
 			orgFile := filepath.Join(examples, "inputs", "a-private-note")
 			err := builder.EvalGoInputs([]string{
@@ -101,15 +145,553 @@ var _ = Describe("GoFlatBuilder", func() {
 		})
 		It("should create destination input file", func() {
 			pipesFile := filepath.Join(examples, "pipes", "pipes.go")
-			err := builder.EvalGoPipes(pipesFile)
+			err := builder.EvalGoPipes([]string{pipesFile})
 			Expect(err).To(BeNil())
 			flat := builder.Flat()
-			Expect(flat.CustomPipes).ToNot(BeEmpty())
+			Expect(len(flat.CustomPipes)).To(Equal(1))
 
-			newFileInfo, err := os.Stat(flat.CustomPipes)
+			data, err := ioutil.ReadFile(flat.CustomPipes[0])
 			Expect(err).To(BeNil())
-			orgFileInfo, _ := os.Stat(pipesFile)
-			Expect(orgFileInfo.Size()).To(Equal(newFileInfo.Size()))
+			Expect(string(data)).To(ContainSubstring("func goflat1_CustomPipes() template.FuncMap {"))
+			Expect(string(data)).To(ContainSubstring("func goflat1_CustomPipesOverrides() []string {"))
+		})
+		It("should add a pipes file named by a header and a flag once", func() {
+			err := builder.EvalGoPipes([]string{filepath.Join(examples, "pipes", "pipes.go"), examples + "/pipes/../pipes/pipes.go"})
+			Expect(err).To(BeNil())
+			Expect(len(builder.Flat().CustomPipes)).To(Equal(1))
+			Expect(builder.Flat().CustomProviders).To(HaveLen(1))
+		})
+		It("should detect the custom pipes info", func() {
+			err := builder.EvalGoPipes([]string{filepath.Join(examples, "pipes", "pipes.go")})
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().CustomPipesInfo).To(Equal([]string{"goflat1_CustomPipesInfo"}))
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring("pipes.DescribeCustom(goflat1_CustomPipesInfo())"))
+		})
+		It("should discover the providers of files and directories in order", func() {
+			pipesDir, _ := ioutil.TempDir(os.TempDir(), "")
+			defer os.RemoveAll(pipesDir)
+			files := map[string]string{
+				"b.go": `package pipes
+				import tt "text/template"
+				func StringPipes() tt.FuncMap { return tt.FuncMap{} }
+				func helper() string { return "" }`,
+				"a.go": `package pipes
+				import "text/template"
+				func MathPipes() template.FuncMap { return nil }
+				func NotAProvider(s string) template.FuncMap { return nil }`,
+				"a_test.go": `package pipes
+				import "text/template"
+				func TestPipes() template.FuncMap { return nil }`,
+			}
+			for k, v := range files {
+				err := ioutil.WriteFile(filepath.Join(pipesDir, k), []byte(v), 0666)
+				Expect(err).To(BeNil())
+			}
+			pipesFile := filepath.Join(examples, "pipes", "pipes.go")
+
+			err := builder.EvalGoPipes([]string{pipesFile, pipesDir, pipesFile})
+			Expect(err).To(BeNil())
+			flat := builder.Flat()
+			Expect(len(flat.CustomPipes)).To(Equal(3))
+			Expect(flat.CustomProviders).To(HaveLen(3))
+			Expect(flat.CustomProviders[0].Name).To(Equal(pipesFile + ":CustomPipes"))
+			Expect(flat.CustomProviders[1].Name).To(Equal(filepath.Join(pipesDir, "a.go") + ":MathPipes"))
+			Expect(flat.CustomProviders[2].Name).To(Equal(filepath.Join(pipesDir, "b.go") + ":StringPipes"))
+			Expect(flat.CustomProviders[0].Func).To(Equal("goflat1_CustomPipes"))
+			Expect(flat.CustomProviders[1].Func).To(Equal("goflat2_MathPipes"))
+			Expect(flat.CustomProviders[2].Func).To(Equal("goflat2_StringPipes"))
+
+			data, err := ioutil.ReadFile(flat.CustomPipes[1])
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(HavePrefix("package main\n"))
+		})
+		It("should compile the packages with the same provider and helper names", func() {
+			pipesDir, _ := ioutil.TempDir(os.TempDir(), "")
+			defer os.RemoveAll(pipesDir)
+			pipes := []string{}
+			for _, v := range []string{"first", "second"} {
+				dir := filepath.Join(pipesDir, v)
+				Expect(os.Mkdir(dir, 0777)).To(Succeed())
+				files := map[string]string{
+					"pipes.go": `package pipes
+				import "text/template"
+				func CustomPipes() template.FuncMap {
+					return template.FuncMap{"` + v + `": func() string { return helper(name) }}
+				}
+				func CustomPipesInfo() map[string]struct {
+					Description string
+					Examples    []string
+					Deprecated  string
+				} {
+					return nil
+				}`,
+					"helper.go": `package pipes
+				const name = "` + v + `"
+				func helper(name string) string { return name + "!" }`,
+				}
+				for k, f := range files {
+					Expect(ioutil.WriteFile(filepath.Join(dir, k), []byte(f), 0666)).To(Succeed())
+				}
+				pipes = append(pipes, dir)
+			}
+			err := builder.EvalGoPipes(pipes)
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			flat := builder.Flat()
+			Expect(flat.CustomProviders).To(HaveLen(2))
+			Expect(flat.CustomProviders[0].Func).To(Equal("goflat1_CustomPipes"))
+			Expect(flat.CustomProviders[1].Func).To(Equal("goflat2_CustomPipes"))
+
+			out, err := goBuild(flat)
+			Expect(err).To(BeNil(), string(out))
+		})
+		It("should catch pipes without a provider", func() {
+			pipesFile := filepath.Join(examples, "inputs", "private.go")
+			err := builder.EvalGoPipes([]string{pipesFile})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrPipesUndefined))
+		})
+		It("should catch an invalid pipes file", func() {
+			err := builder.EvalGoPipes([]string{filepath.Join(examples, "template.yml")})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidPipes))
+		})
+	})
+	Context("#EvalPipesOverride", func() {
+		var builder FlatBuilder
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.yml")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+		})
+		It("should warn by default", func() {
+			err := builder.EvalPipesOverride("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().PipesOverride).To(Equal(DefaultPipesOverride))
+		})
+		It("should apply the policy with the declared overrides", func() {
+			err := builder.EvalGoPipes([]string{filepath.Join(examples, "pipes", "pipes.go")})
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().CustomPipesOverrides).To(Equal([]string{"goflat1_CustomPipesOverrides"}))
+			err = builder.EvalPipesOverride("error")
+			Expect(err).To(BeNil())
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(`overrides = append(overrides, goflat1_CustomPipesOverrides()...)`))
+			Expect(data).To(ContainSubstring(`}, overrides, "error")`))
+		})
+		It("should catch an unknown policy", func() {
+			err := builder.EvalPipesOverride("INVALID")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidOverride))
+		})
+	})
+	Context("#EvalFileRoot", func() {
+		var builder FlatBuilder
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.yml")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+		})
+		It("should default to the template directory", func() {
+			err := builder.EvalFileRoot("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().FileDir).To(Equal(examples))
+			Expect(builder.Flat().FileRoot).To(Equal(examples))
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(fmt.Sprintf("pipes.SetFileRoot(%q, %q)", examples, examples)))
+		})
+		It("should set the root directory", func() {
+			err := builder.EvalFileRoot(filepath.Dir(examples))
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().FileDir).To(Equal(examples))
+			Expect(builder.Flat().FileRoot).To(Equal(filepath.Dir(examples)))
+		})
+		It("should catch an invalid root directory", func() {
+			err := builder.EvalFileRoot(filepath.Join(examples, "template.yml"))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrNotDirectory))
+			err = builder.EvalFileRoot("/WRONG/DIR")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrMissingOnDisk))
+		})
+	})
+	Context("#EvalAllowEnv", func() {
+		var builder FlatBuilder
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.yml")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+		})
+		It("should allow the patterns", func() {
+			err := builder.EvalAllowEnv([]string{"CI_*", "HOME"})
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(`pipes.AllowEnv("CI_*", "HOME", )`))
+		})
+		It("should catch a malformed pattern", func() {
+			err := builder.EvalAllowEnv([]string{"CI_["})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidEnvPattern))
+		})
+	})
+	Context("#EvalPartials", func() {
+		var (
+			builder     FlatBuilder
+			partialsDir string
+		)
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.yml")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+			partialsDir, _ = ioutil.TempDir(os.TempDir(), "")
+			for _, v := range []string{"job.tmpl", "a.tmpl", ".hidden", "notes.txt"} {
+				err = ioutil.WriteFile(filepath.Join(partialsDir, v), []byte(`{{define "x"}}{{end}}`), 0666)
+				Expect(err).To(BeNil())
+			}
+			Expect(os.Mkdir(filepath.Join(partialsDir, "nested"), 0777)).To(Succeed())
+		})
+		AfterEach(func() {
+			os.RemoveAll(partialsDir)
+		})
+		It("should add the files of directories and globs in order", func() {
+			err := builder.EvalPartials([]string{filepath.Join(partialsDir, "*.txt"), partialsDir})
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().Partials).To(Equal([]string{
+				filepath.Join(partialsDir, "notes.txt"),
+				filepath.Join(partialsDir, "a.tmpl"),
+				filepath.Join(partialsDir, "job.tmpl"),
+				filepath.Join(partialsDir, "notes.txt"),
+			}))
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(fmt.Sprintf("%q, ", filepath.Join(partialsDir, "job.tmpl"))))
+			Expect(data).To(ContainSubstring("tmpl.New(v).Parse(string(partial))"))
+		})
+		It("should catch missing partials", func() {
+			err := builder.EvalPartials([]string{filepath.Join(partialsDir, "*.yml")})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrMissingOnDisk))
+		})
+	})
+	Context("#EvalDelims", func() {
+		var templateDir string
+		BeforeEach(func() {
+			templateDir, _ = ioutil.TempDir(os.TempDir(), "")
+		})
+		AfterEach(func() {
+			os.RemoveAll(templateDir)
+		})
+		newBuilder := func(text string) FlatBuilder {
+			template := filepath.Join(templateDir, "workflow.yml")
+			Expect(ioutil.WriteFile(template, []byte(text), 0666)).To(Succeed())
+			builder, err := NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+			return builder
+		}
+		It("should keep the default delimiters", func() {
+			builder := newBuilder("name: {{.Name}}")
+			err := builder.EvalDelims("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().LeftDelim).To(Equal(""))
+			Expect(builder.Flat().RightDelim).To(Equal(""))
+		})
+		It("should set the delimiters", func() {
+			builder := newBuilder("name: [[.Name]]")
+			err := builder.EvalDelims("[[,]]")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().LeftDelim).To(Equal("[["))
+			Expect(builder.Flat().RightDelim).To(Equal("]]"))
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(`Delims("[[", "]]")`))
+		})
+		It("should use the delimiters of the template header", func() {
+			builder := newBuilder(`<%- /* delims "<%" "%>" */ -%>
+run: ${{ github.sha }} <% .Name %>`)
+			for _, v := range []string{"", "<%,%>"} {
+				err := builder.EvalDelims(v)
+				Expect(err).To(BeNil())
+				Expect(builder.Flat().LeftDelim).To(Equal("<%"))
+				Expect(builder.Flat().RightDelim).To(Equal("%>"))
+			}
+		})
+		It("should catch delimiters conflicting with the template header", func() {
+			builder := newBuilder(`<%- /* delims "<%" "%>" */ -%>
+run: ${{ github.sha }} <% .Name %>`)
+			err := builder.EvalDelims("[[,]]")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrConflictingDelims))
+			Expect(err.Error()).To(ContainSubstring("declares <%,%>"))
+		})
+		It("should ignore a header in other delimiters", func() {
+			builder := newBuilder(`{{/* delims "[[" "]]" */}}`)
+			err := builder.EvalDelims("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().LeftDelim).To(Equal(""))
+		})
+		It("should use the delimiters of the front-matter", func() {
+			builder := newBuilder("[[/* goflat\ndelims: [[,]]\n*/]]name: [[.Name]]")
+			err := builder.EvalDelims("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().LeftDelim).To(Equal("[["))
+			Expect(builder.Flat().RightDelim).To(Equal("]]"))
+		})
+		It("should catch invalid delimiters", func() {
+			builder := newBuilder("name: [[.Name]]")
+			for _, v := range []string{"[[", "[[,", "[[,]],]]"} {
+				err := builder.EvalDelims(v)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(ErrInvalidDelims))
+			}
+		})
+	})
+	Context("#EvalStrict", func() {
+		It("should check the output and the unused input fields", func() {
+			template := filepath.Join(examples, "template.yml")
+			builder, err := NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+			err = builder.EvalStrict(true)
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(`tmpl.Option("missingkey=error")`))
+			Expect(data).To(ContainSubstring("CheckOutput(output.Bytes())"))
+			Expect(data).To(ContainSubstring("UnusedFields(tmpl, result)"))
+		})
+	})
+	Context("#EvalEngine", func() {
+		var builder FlatBuilder
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.yml")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+		})
+		It("should use text/template by default", func() {
+			Expect(builder.Flat().Engine).To(Equal(DefaultEngine))
+			err := builder.EvalEngine("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().Engine).To(Equal("text"))
+		})
+		It("should execute the template with html/template", func() {
+			err := builder.EvalEngine("html")
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring("pipes.HTMLTemplate(tmpl)"))
+			Expect(data).To(ContainSubstring("htmpl.Execute(&output, result)"))
+		})
+		It("should catch an unknown engine", func() {
+			err := builder.EvalEngine("xml")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidEngine))
+		})
+	})
+	Context("#EvalFormat", func() {
+		var builder FlatBuilder
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.json")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+		})
+		It("should not check a text output", func() {
+			Expect(builder.Flat().Format).To(Equal(DefaultFormat))
+			err := builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).ToNot(ContainSubstring("CheckFormat"))
+		})
+		It("should check a json output", func() {
+			err := builder.EvalFormat("json")
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(`CheckFormat("json", output.Bytes())`))
+		})
+		It("should execute a html output with html/template", func() {
+			err := builder.EvalFormat("html")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().Engine).To(Equal("html"))
+		})
+		It("should catch an unknown format", func() {
+			err := builder.EvalFormat("toml")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidOutputFormat))
+		})
+	})
+	Context("#EvalOutputDir", func() {
+		var templateDir string
+		BeforeEach(func() {
+			templateDir, _ = ioutil.TempDir(os.TempDir(), "")
+			files := map[string]string{
+				"pipeline.yml.tmpl":       "name: {{.Repos.Name}}",
+				"ci/job.html.tmpl":        "[[/* goflat\ndelims: [[,]]\nformat: html\n*/]]<p>[[.Repos.Name]]</p>",
+				"ci/deploy.sh":            "echo {{ .Repos.Name }}",
+				"_helpers.tmpl":           `{{define "x"}}{{end}}`,
+				"_layouts/base.yml.tmpl":  "",
+				"{{.Repos.Name}}-vars.yml": "a: 1",
+			}
+			for k, v := range files {
+				file := filepath.Join(templateDir, k)
+				Expect(os.MkdirAll(filepath.Dir(file), 0777)).To(Succeed())
+				Expect(ioutil.WriteFile(file, []byte(v), 0666)).To(Succeed())
+			}
+		})
+		AfterEach(func() {
+			os.RemoveAll(templateDir)
+		})
+		It("should render the templates and copy the other files", func() {
+			builder, err := NewFlatBuilder(tmpDir, templateDir)
+			Expect(err).To(BeNil())
+			outputDir := filepath.Join(templateDir, "out")
+			Expect(os.MkdirAll(outputDir, 0777)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(outputDir, "pipeline.yml"), []byte(""), 0666)).To(Succeed())
+			err = builder.EvalOutputDir(outputDir)
+			Expect(err).To(BeNil())
+			flat := builder.Flat()
+			Expect(flat.OutputDir).To(Equal(outputDir))
+			Expect(flat.FileDir).To(Equal(templateDir))
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(flat.MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(fmt.Sprintf("pipes.RenderTree(%q, \"\", \"\", []goflat_Template{", outputDir)))
+			Expect(data).To(ContainSubstring(fmt.Sprintf(`{File: %q, Name: "{{.Repos.Name}}-vars.yml", Copy: true,`, filepath.Join(templateDir, "{{.Repos.Name}}-vars.yml"))))
+			Expect(data).To(ContainSubstring(fmt.Sprintf(`{File: %q, Name: "ci/deploy.sh", Copy: true,`, filepath.Join(templateDir, "ci", "deploy.sh"))))
+			Expect(data).To(ContainSubstring(fmt.Sprintf(`{File: %q, Name: "ci/job.html", Copy: false, LeftDelim: "[[", RightDelim: "]]",`, filepath.Join(templateDir, "ci", "job.html.tmpl"))))
+			Expect(data).To(ContainSubstring(`Engine: "html", Format: "html"`))
+			Expect(data).To(ContainSubstring(fmt.Sprintf(`{File: %q, Name: "pipeline.yml", Copy: false, LeftDelim: "", RightDelim: "",`, filepath.Join(templateDir, "pipeline.yml.tmpl"))))
+			Expect(data).ToNot(ContainSubstring("_helpers.tmpl"))
+			Expect(data).ToNot(ContainSubstring("base.yml"))
+			Expect(data).ToNot(ContainSubstring(filepath.Join(outputDir, "pipeline.yml")))
+		})
+		It("should catch delims that conflict with the header of a template", func() {
+			builder, err := NewFlatBuilder(tmpDir, templateDir)
+			Expect(err).To(BeNil())
+			err = builder.EvalDelims("<<,>>")
+			Expect(err).To(BeNil())
+			err = builder.EvalOutputDir(filepath.Join(templateDir, "out"))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrConflictingDelims))
+			Expect(err.Error()).To(ContainSubstring(filepath.Join(templateDir, "ci", "job.html.tmpl")))
+		})
+		It("should accept delims that match the header of a template", func() {
+			builder, err := NewFlatBuilder(tmpDir, templateDir)
+			Expect(err).To(BeNil())
+			err = builder.EvalDelims("[[,]]")
+			Expect(err).To(BeNil())
+			err = builder.EvalOutputDir(filepath.Join(templateDir, "out"))
+			Expect(err).To(BeNil())
+		})
+		It("should catch a template file", func() {
+			builder, err := NewFlatBuilder(tmpDir, filepath.Join(templateDir, "pipeline.yml.tmpl"))
+			Expect(err).To(BeNil())
+			err = builder.EvalOutputDir(filepath.Join(templateDir, "out"))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrNotDirectory))
+		})
+	})
+	Context("#EvalListPipes", func() {
+		It("should list the pipes without a template", func() {
+			builder, err := NewFlatBuilder(tmpDir, "")
+			Expect(err).To(BeNil())
+			err = builder.EvalListPipes("json")
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring(`pipes.Print(os.Stdout, "json")`))
+			Expect(data).ToNot(ContainSubstring("ioutil.ReadFile"))
+		})
+		It("should catch an unknown format", func() {
+			builder, err := NewFlatBuilder(tmpDir, "")
+			Expect(err).To(BeNil())
+			err = builder.EvalListPipes("xml")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidFormat))
+		})
+		It("should catch a missing template when rendering", func() {
+			builder, err := NewFlatBuilder(tmpDir, "")
+			Expect(err).To(BeNil())
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			flat := builder.Flat()
+			err = flat.GoRun(ioutil.Discard, ioutil.Discard)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrTemplateUndefined))
+		})
+	})
+	Context("#EvalNow", func() {
+		var builder FlatBuilder
+		BeforeEach(func() {
+			var err error
+			template := filepath.Join(examples, "template.yml")
+			builder, err = NewFlatBuilder(tmpDir, template)
+			Expect(err).To(BeNil())
+		})
+		It("should pin the clock", func() {
+			err := builder.EvalNow("2016-03-20T15:04:05Z")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().Now).To(Equal("2016-03-20T15:04:05Z"))
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).To(ContainSubstring("pipes.SetNow(now)"))
+		})
+		It("should use the current time by default", func() {
+			err := builder.EvalNow("")
+			Expect(err).To(BeNil())
+			Expect(builder.Flat().Now).To(BeEmpty())
+
+			err = builder.EvalMainGo()
+			Expect(err).To(BeNil())
+			data, err := ioutil.ReadFile(builder.Flat().MainGo)
+			Expect(err).To(BeNil())
+			Expect(data).ToNot(ContainSubstring("pipes.SetNow(now)"))
+		})
+		It("should catch an invalid timestamp", func() {
+			err := builder.EvalNow("yesterday")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrInvalidNow))
 		})
 	})
 	Context("#EvalMainGo", func() {
@@ -0,0 +1,176 @@
+package goflat
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+//Header is the front-matter of a template, a comment at its beginning written in the delimiters of the template
+//declaring its inputs, pipes, output, delimiters and output format, e.g.
+//
+//	{{/* goflat
+//	inputs:
+//	  - inputs/repos.go
+//	  - inputs/a-private-note:Private
+//	pipes: pipes.go
+//	output: pipeline.yml
+//	delims: [[,]]
+//	format: json
+//	*/}}
+//
+//the paths are relative to the directory of the template
+type Header struct {
+	Inputs []string
+	Pipes  []string
+	Output string
+	Delims string
+	Format string
+}
+
+var frontMatter = regexp.MustCompile(`(?s)^\s*\S*?\s*/\*\s*goflat[ \t]*\r?\n(.*?)\*/`)
+
+//ReadHeader returns the header of a template, an empty header when it has none,
+//the declared inputs and pipes must exist. The header of a template directory has the inputs and the pipes
+//of its templates
+func ReadHeader(template string) (Header, error) {
+	if template == "" {
+		return Header{}, nil
+	}
+	if fi, err := os.Stat(template); err == nil && fi.IsDir() {
+		return readTreeHeader(template)
+	}
+	data, err := ioutil.ReadFile(template)
+	if err != nil {
+		return Header{}, fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	}
+	h, err := parseHeader(string(data))
+	if err != nil {
+		return Header{}, fmt.Errorf("%s:%s:%s", ErrInvalidHeader, template, err.Error())
+	}
+	dir := filepath.Dir(template)
+	for k, v := range h.Inputs {
+		path, name := v, ""
+		if i := strings.Index(v, ":"); i >= 0 {
+			path, name = v[:i], v[i:]
+		}
+		path = headerPath(dir, path)
+		if _, err := os.Stat(path); err != nil {
+			return Header{}, fmt.Errorf("%s:input %s declared by %s", ErrMissingDeclared, v, template)
+		}
+		h.Inputs[k] = path + name
+	}
+	for k, v := range h.Pipes {
+		h.Pipes[k] = headerPath(dir, v)
+		if _, err := os.Stat(h.Pipes[k]); err != nil {
+			return Header{}, fmt.Errorf("%s:pipes %s declared by %s", ErrMissingDeclared, v, template)
+		}
+	}
+	if h.Output != "" {
+		h.Output = headerPath(dir, h.Output)
+	}
+	return h, nil
+}
+
+//readTreeHeader merges the inputs and the pipes of the templates of a directory
+func readTreeHeader(dir string) (Header, error) {
+	files, err := treeFiles(dir, "")
+	if err != nil {
+		return Header{}, fmt.Errorf("%s:%s", ErrMissingOnDisk, err.Error())
+	}
+	tree := Header{}
+	seen := make(map[string]bool)
+	for _, v := range files {
+		if !strings.HasSuffix(v, ".tmpl") {
+			continue
+		}
+		h, err := ReadHeader(v)
+		if err != nil {
+			return Header{}, err
+		}
+		for _, i := range h.Inputs {
+			if !seen["inputs:"+i] {
+				seen["inputs:"+i] = true
+				tree.Inputs = append(tree.Inputs, i)
+			}
+		}
+		for _, p := range h.Pipes {
+			if !seen["pipes:"+p] {
+				seen["pipes:"+p] = true
+				tree.Pipes = append(tree.Pipes, p)
+			}
+		}
+	}
+	return tree, nil
+}
+
+func headerPath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+//parseHeader parses the "key: value" lines of the front-matter, a list is either a value or the "- value" lines
+//following its key
+func parseHeader(text string) (Header, error) {
+	h := Header{}
+	m := frontMatter.FindStringSubmatch(text)
+	if m == nil {
+		return h, nil
+	}
+	key := ""
+	for k, line := range strings.Split(m[1], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "- ") {
+			value := strings.TrimSpace(line[2:])
+			switch key {
+			case "inputs":
+				h.Inputs = append(h.Inputs, value)
+			case "pipes":
+				h.Pipes = append(h.Pipes, value)
+			default:
+				return h, fmt.Errorf("line %d: %s is not a list", k+1, key)
+			}
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return h, fmt.Errorf("line %d: %q is not a key: value", k+1, line)
+		}
+		key = strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+		switch key {
+		case "inputs":
+			if value != "" {
+				h.Inputs = append(h.Inputs, value)
+			}
+		case "pipes":
+			if value != "" {
+				h.Pipes = append(h.Pipes, value)
+			}
+		case "output":
+			h.Output = value
+		case "delims":
+			h.Delims = value
+		case "format":
+			h.Format = value
+		default:
+			return h, fmt.Errorf("line %d: unknown key %q", k+1, key)
+		}
+	}
+	return h, nil
+}
+
+const (
+	//ErrInvalidHeader Expected error for a template header that cannot be parsed
+	ErrInvalidHeader = "(template header cannot be parsed)"
+	//ErrMissingDeclared Expected error for an input or a pipes file declared by the template header that is missing
+	ErrMissingDeclared = "(file declared by the template header is missing)"
+)
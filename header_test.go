@@ -0,0 +1,108 @@
+package goflat_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/aminjam/goflat"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Header", func() {
+	var (
+		templateDir string
+		examples    string
+	)
+	BeforeEach(func() {
+		templateDir, _ = ioutil.TempDir(os.TempDir(), "")
+		wd, _ := os.Getwd()
+		examples = filepath.Join(wd, ".examples")
+		Expect(os.Mkdir(filepath.Join(templateDir, "inputs"), 0777)).To(Succeed())
+		for _, v := range []string{"inputs/repos.go", "inputs/a-private-note", "pipes.go"} {
+			Expect(ioutil.WriteFile(filepath.Join(templateDir, v), []byte("package main"), 0666)).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(templateDir)
+	})
+	write := func(text string) string {
+		template := filepath.Join(templateDir, "pipeline.yml.tmpl")
+		Expect(ioutil.WriteFile(template, []byte(text), 0666)).To(Succeed())
+		return template
+	}
+
+	Context("#ReadHeader", func() {
+		It("should read the declarations relative to the template", func() {
+			template := write(`{{- /* goflat
+inputs:
+  - inputs/repos.go
+  - inputs/a-private-note:Private
+# the custom pipes
+pipes: pipes.go
+output: pipeline.yml
+delims: [[,]]
+format: json
+*/ -}}
+name: {{.Repos.Name}}`)
+			h, err := ReadHeader(template)
+			Expect(err).To(BeNil())
+			Expect(h).To(Equal(Header{
+				Inputs: []string{
+					filepath.Join(templateDir, "inputs", "repos.go"),
+					filepath.Join(templateDir, "inputs", "a-private-note") + ":Private",
+				},
+				Pipes:  []string{filepath.Join(templateDir, "pipes.go")},
+				Output: filepath.Join(templateDir, "pipeline.yml"),
+				Delims: "[[,]]",
+				Format: "json",
+			}))
+		})
+		It("should read a template without a header", func() {
+			h, err := ReadHeader(filepath.Join(examples, "template.yml"))
+			Expect(err).To(BeNil())
+			Expect(h).To(Equal(Header{}))
+			h, err = ReadHeader("")
+			Expect(err).To(BeNil())
+			Expect(h).To(Equal(Header{}))
+		})
+		It("should merge the inputs and the pipes of a template directory", func() {
+			write("{{/* goflat\ninputs: inputs/repos.go\npipes: pipes.go\noutput: a.yml\n*/}}")
+			Expect(os.Mkdir(filepath.Join(templateDir, "ci"), 0777)).To(Succeed())
+			err := ioutil.WriteFile(filepath.Join(templateDir, "ci", "job.yml.tmpl"),
+				[]byte("{{/* goflat\ninputs:\n  - ../inputs/repos.go\n  - ../inputs/a-private-note:Private\n*/}}"), 0666)
+			Expect(err).To(BeNil())
+			h, err := ReadHeader(templateDir)
+			Expect(err).To(BeNil())
+			Expect(h).To(Equal(Header{
+				Inputs: []string{
+					filepath.Join(templateDir, "inputs", "repos.go"),
+					filepath.Join(templateDir, "inputs", "a-private-note") + ":Private",
+				},
+				Pipes: []string{filepath.Join(templateDir, "pipes.go")},
+			}))
+		})
+		It("should catch a missing declared input", func() {
+			template := write("{{/* goflat\ninputs: inputs/private.go\n*/}}")
+			_, err := ReadHeader(template)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrMissingDeclared))
+			Expect(err.Error()).To(ContainSubstring("input inputs/private.go declared by " + template))
+		})
+		It("should catch missing declared pipes", func() {
+			template := write("{{/* goflat\npipes:\n  - helpers.go\n*/}}")
+			_, err := ReadHeader(template)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(ErrMissingDeclared))
+		})
+		It("should catch an invalid header", func() {
+			for _, v := range []string{"{{/* goflat\ninput: inputs/repos.go\n*/}}", "{{/* goflat\noutput\n*/}}", "{{/* goflat\noutput:\n  - a.yml\n*/}}"} {
+				_, err := ReadHeader(write(v))
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(ErrInvalidHeader))
+			}
+		})
+	})
+})
@@ -1,13 +1,509 @@
 package goflat
 
 const (
+	BcryptGo = `package runtime
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	bcryptCost = 10
+	//blowfish only expands the first 72 bytes of the key
+	bcryptMaxPassword = 72
+)
+
+var bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+//bcryptHash returns the $2a$ hash of the password with a random salt
+func bcryptHash(password string) (string, error) {
+	if len(password) > bcryptMaxPassword {
+		return "", fmt.Errorf("bcrypt: password is longer than %d bytes", bcryptMaxPassword)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("bcrypt: %s", err.Error())
+	}
+	return bcryptWithSalt([]byte(password), bcryptCost, salt), nil
+}
+
+//bcryptWithSalt is the eksblowfish setup followed by 64 encryptions of the magic text
+func bcryptWithSalt(password []byte, cost uint, salt []byte) string {
+	//C implementations include the trailing NUL of the password in the key
+	key := append(password[:len(password):len(password)], 0)
+	c := newBlowfish()
+	c.expand(key, salt)
+	for i := uint64(0); i < 1<<cost; i++ {
+		c.expand(key, nil)
+		c.expand(salt, nil)
+	}
+	text := []byte("OrpheanBeholderScryDoubt")
+	for i := 0; i < len(text); i += 8 {
+		l, r := binary.BigEndian.Uint32(text[i:]), binary.BigEndian.Uint32(text[i+4:])
+		for j := 0; j < 64; j++ {
+			l, r = c.encrypt(l, r)
+		}
+		binary.BigEndian.PutUint32(text[i:], l)
+		binary.BigEndian.PutUint32(text[i+4:], r)
+	}
+	//C implementations only encode 23 of the 24 bytes
+	return fmt.Sprintf("$2a$%02d$%s%s", cost, bcryptEncoding.EncodeToString(salt), bcryptEncoding.EncodeToString(text[:23]))
+}
+
+type blowfish struct {
+	p [18]uint32
+	s [4][256]uint32
+}
+
+func newBlowfish() *blowfish {
+	return &blowfish{
+		p: blowfishP,
+		s: [4][256]uint32{blowfishS0, blowfishS1, blowfishS2, blowfishS3},
+	}
+}
+
+func (c *blowfish) f(x uint32) uint32 {
+	return ((c.s[0][byte(x>>24)] + c.s[1][byte(x>>16)]) ^ c.s[2][byte(x>>8)]) + c.s[3][byte(x)]
+}
+
+func (c *blowfish) encrypt(l, r uint32) (uint32, uint32) {
+	l ^= c.p[0]
+	for i := 1; i < 17; i += 2 {
+		r ^= c.f(l) ^ c.p[i]
+		l ^= c.f(r) ^ c.p[i+1]
+	}
+	return r ^ c.p[17], l
+}
+
+//expand mixes the key into the subkeys, the salt is only mixed in by the first expansion
+func (c *blowfish) expand(key, salt []byte) {
+	j := 0
+	for i := range c.p {
+		c.p[i] ^= blowfishWord(key, &j)
+	}
+	j = 0
+	var l, r uint32
+	for _, box := range [][]uint32{c.p[:], c.s[0][:], c.s[1][:], c.s[2][:], c.s[3][:]} {
+		for i := 0; i < len(box); i += 2 {
+			if salt != nil {
+				l ^= blowfishWord(salt, &j)
+				r ^= blowfishWord(salt, &j)
+			}
+			l, r = c.encrypt(l, r)
+			box[i], box[i+1] = l, r
+		}
+	}
+}
+
+//blowfishWord reads the next 4 bytes of b as a word, wrapping around at the end of b
+func blowfishWord(b []byte, pos *int) uint32 {
+	var w uint32
+	for i := 0; i < 4; i++ {
+		w = w<<8 | uint32(b[*pos])
+		*pos = (*pos + 1) % len(b)
+	}
+	return w
+}
+`
+	BlowfishGo = `package runtime
+
+//the blowfish tables are the hexadecimal digits of PI, ported with bcrypt from golang.org/x/crypto
+//(Copyright 2010 The Go Authors, BSD-style license) so the default pipes only need the standard library
+
+var blowfishS0 = [256]uint32{
+	0xd1310ba6, 0x98dfb5ac, 0x2ffd72db, 0xd01adfb7, 0xb8e1afed, 0x6a267e96,
+	0xba7c9045, 0xf12c7f99, 0x24a19947, 0xb3916cf7, 0x0801f2e2, 0x858efc16,
+	0x636920d8, 0x71574e69, 0xa458fea3, 0xf4933d7e, 0x0d95748f, 0x728eb658,
+	0x718bcd58, 0x82154aee, 0x7b54a41d, 0xc25a59b5, 0x9c30d539, 0x2af26013,
+	0xc5d1b023, 0x286085f0, 0xca417918, 0xb8db38ef, 0x8e79dcb0, 0x603a180e,
+	0x6c9e0e8b, 0xb01e8a3e, 0xd71577c1, 0xbd314b27, 0x78af2fda, 0x55605c60,
+	0xe65525f3, 0xaa55ab94, 0x57489862, 0x63e81440, 0x55ca396a, 0x2aab10b6,
+	0xb4cc5c34, 0x1141e8ce, 0xa15486af, 0x7c72e993, 0xb3ee1411, 0x636fbc2a,
+	0x2ba9c55d, 0x741831f6, 0xce5c3e16, 0x9b87931e, 0xafd6ba33, 0x6c24cf5c,
+	0x7a325381, 0x28958677, 0x3b8f4898, 0x6b4bb9af, 0xc4bfe81b, 0x66282193,
+	0x61d809cc, 0xfb21a991, 0x487cac60, 0x5dec8032, 0xef845d5d, 0xe98575b1,
+	0xdc262302, 0xeb651b88, 0x23893e81, 0xd396acc5, 0x0f6d6ff3, 0x83f44239,
+	0x2e0b4482, 0xa4842004, 0x69c8f04a, 0x9e1f9b5e, 0x21c66842, 0xf6e96c9a,
+	0x670c9c61, 0xabd388f0, 0x6a51a0d2, 0xd8542f68, 0x960fa728, 0xab5133a3,
+	0x6eef0b6c, 0x137a3be4, 0xba3bf050, 0x7efb2a98, 0xa1f1651d, 0x39af0176,
+	0x66ca593e, 0x82430e88, 0x8cee8619, 0x456f9fb4, 0x7d84a5c3, 0x3b8b5ebe,
+	0xe06f75d8, 0x85c12073, 0x401a449f, 0x56c16aa6, 0x4ed3aa62, 0x363f7706,
+	0x1bfedf72, 0x429b023d, 0x37d0d724, 0xd00a1248, 0xdb0fead3, 0x49f1c09b,
+	0x075372c9, 0x80991b7b, 0x25d479d8, 0xf6e8def7, 0xe3fe501a, 0xb6794c3b,
+	0x976ce0bd, 0x04c006ba, 0xc1a94fb6, 0x409f60c4, 0x5e5c9ec2, 0x196a2463,
+	0x68fb6faf, 0x3e6c53b5, 0x1339b2eb, 0x3b52ec6f, 0x6dfc511f, 0x9b30952c,
+	0xcc814544, 0xaf5ebd09, 0xbee3d004, 0xde334afd, 0x660f2807, 0x192e4bb3,
+	0xc0cba857, 0x45c8740f, 0xd20b5f39, 0xb9d3fbdb, 0x5579c0bd, 0x1a60320a,
+	0xd6a100c6, 0x402c7279, 0x679f25fe, 0xfb1fa3cc, 0x8ea5e9f8, 0xdb3222f8,
+	0x3c7516df, 0xfd616b15, 0x2f501ec8, 0xad0552ab, 0x323db5fa, 0xfd238760,
+	0x53317b48, 0x3e00df82, 0x9e5c57bb, 0xca6f8ca0, 0x1a87562e, 0xdf1769db,
+	0xd542a8f6, 0x287effc3, 0xac6732c6, 0x8c4f5573, 0x695b27b0, 0xbbca58c8,
+	0xe1ffa35d, 0xb8f011a0, 0x10fa3d98, 0xfd2183b8, 0x4afcb56c, 0x2dd1d35b,
+	0x9a53e479, 0xb6f84565, 0xd28e49bc, 0x4bfb9790, 0xe1ddf2da, 0xa4cb7e33,
+	0x62fb1341, 0xcee4c6e8, 0xef20cada, 0x36774c01, 0xd07e9efe, 0x2bf11fb4,
+	0x95dbda4d, 0xae909198, 0xeaad8e71, 0x6b93d5a0, 0xd08ed1d0, 0xafc725e0,
+	0x8e3c5b2f, 0x8e7594b7, 0x8ff6e2fb, 0xf2122b64, 0x8888b812, 0x900df01c,
+	0x4fad5ea0, 0x688fc31c, 0xd1cff191, 0xb3a8c1ad, 0x2f2f2218, 0xbe0e1777,
+	0xea752dfe, 0x8b021fa1, 0xe5a0cc0f, 0xb56f74e8, 0x18acf3d6, 0xce89e299,
+	0xb4a84fe0, 0xfd13e0b7, 0x7cc43b81, 0xd2ada8d9, 0x165fa266, 0x80957705,
+	0x93cc7314, 0x211a1477, 0xe6ad2065, 0x77b5fa86, 0xc75442f5, 0xfb9d35cf,
+	0xebcdaf0c, 0x7b3e89a0, 0xd6411bd3, 0xae1e7e49, 0x00250e2d, 0x2071b35e,
+	0x226800bb, 0x57b8e0af, 0x2464369b, 0xf009b91e, 0x5563911d, 0x59dfa6aa,
+	0x78c14389, 0xd95a537f, 0x207d5ba2, 0x02e5b9c5, 0x83260376, 0x6295cfa9,
+	0x11c81968, 0x4e734a41, 0xb3472dca, 0x7b14a94a, 0x1b510052, 0x9a532915,
+	0xd60f573f, 0xbc9bc6e4, 0x2b60a476, 0x81e67400, 0x08ba6fb5, 0x571be91f,
+	0xf296ec6b, 0x2a0dd915, 0xb6636521, 0xe7b9f9b6, 0xff34052e, 0xc5855664,
+	0x53b02d5d, 0xa99f8fa1, 0x08ba4799, 0x6e85076a,
+}
+
+var blowfishS1 = [256]uint32{
+	0x4b7a70e9, 0xb5b32944, 0xdb75092e, 0xc4192623, 0xad6ea6b0, 0x49a7df7d,
+	0x9cee60b8, 0x8fedb266, 0xecaa8c71, 0x699a17ff, 0x5664526c, 0xc2b19ee1,
+	0x193602a5, 0x75094c29, 0xa0591340, 0xe4183a3e, 0x3f54989a, 0x5b429d65,
+	0x6b8fe4d6, 0x99f73fd6, 0xa1d29c07, 0xefe830f5, 0x4d2d38e6, 0xf0255dc1,
+	0x4cdd2086, 0x8470eb26, 0x6382e9c6, 0x021ecc5e, 0x09686b3f, 0x3ebaefc9,
+	0x3c971814, 0x6b6a70a1, 0x687f3584, 0x52a0e286, 0xb79c5305, 0xaa500737,
+	0x3e07841c, 0x7fdeae5c, 0x8e7d44ec, 0x5716f2b8, 0xb03ada37, 0xf0500c0d,
+	0xf01c1f04, 0x0200b3ff, 0xae0cf51a, 0x3cb574b2, 0x25837a58, 0xdc0921bd,
+	0xd19113f9, 0x7ca92ff6, 0x94324773, 0x22f54701, 0x3ae5e581, 0x37c2dadc,
+	0xc8b57634, 0x9af3dda7, 0xa9446146, 0x0fd0030e, 0xecc8c73e, 0xa4751e41,
+	0xe238cd99, 0x3bea0e2f, 0x3280bba1, 0x183eb331, 0x4e548b38, 0x4f6db908,
+	0x6f420d03, 0xf60a04bf, 0x2cb81290, 0x24977c79, 0x5679b072, 0xbcaf89af,
+	0xde9a771f, 0xd9930810, 0xb38bae12, 0xdccf3f2e, 0x5512721f, 0x2e6b7124,
+	0x501adde6, 0x9f84cd87, 0x7a584718, 0x7408da17, 0xbc9f9abc, 0xe94b7d8c,
+	0xec7aec3a, 0xdb851dfa, 0x63094366, 0xc464c3d2, 0xef1c1847, 0x3215d908,
+	0xdd433b37, 0x24c2ba16, 0x12a14d43, 0x2a65c451, 0x50940002, 0x133ae4dd,
+	0x71dff89e, 0x10314e55, 0x81ac77d6, 0x5f11199b, 0x043556f1, 0xd7a3c76b,
+	0x3c11183b, 0x5924a509, 0xf28fe6ed, 0x97f1fbfa, 0x9ebabf2c, 0x1e153c6e,
+	0x86e34570, 0xeae96fb1, 0x860e5e0a, 0x5a3e2ab3, 0x771fe71c, 0x4e3d06fa,
+	0x2965dcb9, 0x99e71d0f, 0x803e89d6, 0x5266c825, 0x2e4cc978, 0x9c10b36a,
+	0xc6150eba, 0x94e2ea78, 0xa5fc3c53, 0x1e0a2df4, 0xf2f74ea7, 0x361d2b3d,
+	0x1939260f, 0x19c27960, 0x5223a708, 0xf71312b6, 0xebadfe6e, 0xeac31f66,
+	0xe3bc4595, 0xa67bc883, 0xb17f37d1, 0x018cff28, 0xc332ddef, 0xbe6c5aa5,
+	0x65582185, 0x68ab9802, 0xeecea50f, 0xdb2f953b, 0x2aef7dad, 0x5b6e2f84,
+	0x1521b628, 0x29076170, 0xecdd4775, 0x619f1510, 0x13cca830, 0xeb61bd96,
+	0x0334fe1e, 0xaa0363cf, 0xb5735c90, 0x4c70a239, 0xd59e9e0b, 0xcbaade14,
+	0xeecc86bc, 0x60622ca7, 0x9cab5cab, 0xb2f3846e, 0x648b1eaf, 0x19bdf0ca,
+	0xa02369b9, 0x655abb50, 0x40685a32, 0x3c2ab4b3, 0x319ee9d5, 0xc021b8f7,
+	0x9b540b19, 0x875fa099, 0x95f7997e, 0x623d7da8, 0xf837889a, 0x97e32d77,
+	0x11ed935f, 0x16681281, 0x0e358829, 0xc7e61fd6, 0x96dedfa1, 0x7858ba99,
+	0x57f584a5, 0x1b227263, 0x9b83c3ff, 0x1ac24696, 0xcdb30aeb, 0x532e3054,
+	0x8fd948e4, 0x6dbc3128, 0x58ebf2ef, 0x34c6ffea, 0xfe28ed61, 0xee7c3c73,
+	0x5d4a14d9, 0xe864b7e3, 0x42105d14, 0x203e13e0, 0x45eee2b6, 0xa3aaabea,
+	0xdb6c4f15, 0xfacb4fd0, 0xc742f442, 0xef6abbb5, 0x654f3b1d, 0x41cd2105,
+	0xd81e799e, 0x86854dc7, 0xe44b476a, 0x3d816250, 0xcf62a1f2, 0x5b8d2646,
+	0xfc8883a0, 0xc1c7b6a3, 0x7f1524c3, 0x69cb7492, 0x47848a0b, 0x5692b285,
+	0x095bbf00, 0xad19489d, 0x1462b174, 0x23820e00, 0x58428d2a, 0x0c55f5ea,
+	0x1dadf43e, 0x233f7061, 0x3372f092, 0x8d937e41, 0xd65fecf1, 0x6c223bdb,
+	0x7cde3759, 0xcbee7460, 0x4085f2a7, 0xce77326e, 0xa6078084, 0x19f8509e,
+	0xe8efd855, 0x61d99735, 0xa969a7aa, 0xc50c06c2, 0x5a04abfc, 0x800bcadc,
+	0x9e447a2e, 0xc3453484, 0xfdd56705, 0x0e1e9ec9, 0xdb73dbd3, 0x105588cd,
+	0x675fda79, 0xe3674340, 0xc5c43465, 0x713e38d8, 0x3d28f89e, 0xf16dff20,
+	0x153e21e7, 0x8fb03d4a, 0xe6e39f2b, 0xdb83adf7,
+}
+
+var blowfishS2 = [256]uint32{
+	0xe93d5a68, 0x948140f7, 0xf64c261c, 0x94692934, 0x411520f7, 0x7602d4f7,
+	0xbcf46b2e, 0xd4a20068, 0xd4082471, 0x3320f46a, 0x43b7d4b7, 0x500061af,
+	0x1e39f62e, 0x97244546, 0x14214f74, 0xbf8b8840, 0x4d95fc1d, 0x96b591af,
+	0x70f4ddd3, 0x66a02f45, 0xbfbc09ec, 0x03bd9785, 0x7fac6dd0, 0x31cb8504,
+	0x96eb27b3, 0x55fd3941, 0xda2547e6, 0xabca0a9a, 0x28507825, 0x530429f4,
+	0x0a2c86da, 0xe9b66dfb, 0x68dc1462, 0xd7486900, 0x680ec0a4, 0x27a18dee,
+	0x4f3ffea2, 0xe887ad8c, 0xb58ce006, 0x7af4d6b6, 0xaace1e7c, 0xd3375fec,
+	0xce78a399, 0x406b2a42, 0x20fe9e35, 0xd9f385b9, 0xee39d7ab, 0x3b124e8b,
+	0x1dc9faf7, 0x4b6d1856, 0x26a36631, 0xeae397b2, 0x3a6efa74, 0xdd5b4332,
+	0x6841e7f7, 0xca7820fb, 0xfb0af54e, 0xd8feb397, 0x454056ac, 0xba489527,
+	0x55533a3a, 0x20838d87, 0xfe6ba9b7, 0xd096954b, 0x55a867bc, 0xa1159a58,
+	0xcca92963, 0x99e1db33, 0xa62a4a56, 0x3f3125f9, 0x5ef47e1c, 0x9029317c,
+	0xfdf8e802, 0x04272f70, 0x80bb155c, 0x05282ce3, 0x95c11548, 0xe4c66d22,
+	0x48c1133f, 0xc70f86dc, 0x07f9c9ee, 0x41041f0f, 0x404779a4, 0x5d886e17,
+	0x325f51eb, 0xd59bc0d1, 0xf2bcc18f, 0x41113564, 0x257b7834, 0x602a9c60,
+	0xdff8e8a3, 0x1f636c1b, 0x0e12b4c2, 0x02e1329e, 0xaf664fd1, 0xcad18115,
+	0x6b2395e0, 0x333e92e1, 0x3b240b62, 0xeebeb922, 0x85b2a20e, 0xe6ba0d99,
+	0xde720c8c, 0x2da2f728, 0xd0127845, 0x95b794fd, 0x647d0862, 0xe7ccf5f0,
+	0x5449a36f, 0x877d48fa, 0xc39dfd27, 0xf33e8d1e, 0x0a476341, 0x992eff74,
+	0x3a6f6eab, 0xf4f8fd37, 0xa812dc60, 0xa1ebddf8, 0x991be14c, 0xdb6e6b0d,
+	0xc67b5510, 0x6d672c37, 0x2765d43b, 0xdcd0e804, 0xf1290dc7, 0xcc00ffa3,
+	0xb5390f92, 0x690fed0b, 0x667b9ffb, 0xcedb7d9c, 0xa091cf0b, 0xd9155ea3,
+	0xbb132f88, 0x515bad24, 0x7b9479bf, 0x763bd6eb, 0x37392eb3, 0xcc115979,
+	0x8026e297, 0xf42e312d, 0x6842ada7, 0xc66a2b3b, 0x12754ccc, 0x782ef11c,
+	0x6a124237, 0xb79251e7, 0x06a1bbe6, 0x4bfb6350, 0x1a6b1018, 0x11caedfa,
+	0x3d25bdd8, 0xe2e1c3c9, 0x44421659, 0x0a121386, 0xd90cec6e, 0xd5abea2a,
+	0x64af674e, 0xda86a85f, 0xbebfe988, 0x64e4c3fe, 0x9dbc8057, 0xf0f7c086,
+	0x60787bf8, 0x6003604d, 0xd1fd8346, 0xf6381fb0, 0x7745ae04, 0xd736fccc,
+	0x83426b33, 0xf01eab71, 0xb0804187, 0x3c005e5f, 0x77a057be, 0xbde8ae24,
+	0x55464299, 0xbf582e61, 0x4e58f48f, 0xf2ddfda2, 0xf474ef38, 0x8789bdc2,
+	0x5366f9c3, 0xc8b38e74, 0xb475f255, 0x46fcd9b9, 0x7aeb2661, 0x8b1ddf84,
+	0x846a0e79, 0x915f95e2, 0x466e598e, 0x20b45770, 0x8cd55591, 0xc902de4c,
+	0xb90bace1, 0xbb8205d0, 0x11a86248, 0x7574a99e, 0xb77f19b6, 0xe0a9dc09,
+	0x662d09a1, 0xc4324633, 0xe85a1f02, 0x09f0be8c, 0x4a99a025, 0x1d6efe10,
+	0x1ab93d1d, 0x0ba5a4df, 0xa186f20f, 0x2868f169, 0xdcb7da83, 0x573906fe,
+	0xa1e2ce9b, 0x4fcd7f52, 0x50115e01, 0xa70683fa, 0xa002b5c4, 0x0de6d027,
+	0x9af88c27, 0x773f8641, 0xc3604c06, 0x61a806b5, 0xf0177a28, 0xc0f586e0,
+	0x006058aa, 0x30dc7d62, 0x11e69ed7, 0x2338ea63, 0x53c2dd94, 0xc2c21634,
+	0xbbcbee56, 0x90bcb6de, 0xebfc7da1, 0xce591d76, 0x6f05e409, 0x4b7c0188,
+	0x39720a3d, 0x7c927c24, 0x86e3725f, 0x724d9db9, 0x1ac15bb4, 0xd39eb8fc,
+	0xed545578, 0x08fca5b5, 0xd83d7cd3, 0x4dad0fc4, 0x1e50ef5e, 0xb161e6f8,
+	0xa28514d9, 0x6c51133c, 0x6fd5c7e7, 0x56e14ec4, 0x362abfce, 0xddc6c837,
+	0xd79a3234, 0x92638212, 0x670efa8e, 0x406000e0,
+}
+
+var blowfishS3 = [256]uint32{
+	0x3a39ce37, 0xd3faf5cf, 0xabc27737, 0x5ac52d1b, 0x5cb0679e, 0x4fa33742,
+	0xd3822740, 0x99bc9bbe, 0xd5118e9d, 0xbf0f7315, 0xd62d1c7e, 0xc700c47b,
+	0xb78c1b6b, 0x21a19045, 0xb26eb1be, 0x6a366eb4, 0x5748ab2f, 0xbc946e79,
+	0xc6a376d2, 0x6549c2c8, 0x530ff8ee, 0x468dde7d, 0xd5730a1d, 0x4cd04dc6,
+	0x2939bbdb, 0xa9ba4650, 0xac9526e8, 0xbe5ee304, 0xa1fad5f0, 0x6a2d519a,
+	0x63ef8ce2, 0x9a86ee22, 0xc089c2b8, 0x43242ef6, 0xa51e03aa, 0x9cf2d0a4,
+	0x83c061ba, 0x9be96a4d, 0x8fe51550, 0xba645bd6, 0x2826a2f9, 0xa73a3ae1,
+	0x4ba99586, 0xef5562e9, 0xc72fefd3, 0xf752f7da, 0x3f046f69, 0x77fa0a59,
+	0x80e4a915, 0x87b08601, 0x9b09e6ad, 0x3b3ee593, 0xe990fd5a, 0x9e34d797,
+	0x2cf0b7d9, 0x022b8b51, 0x96d5ac3a, 0x017da67d, 0xd1cf3ed6, 0x7c7d2d28,
+	0x1f9f25cf, 0xadf2b89b, 0x5ad6b472, 0x5a88f54c, 0xe029ac71, 0xe019a5e6,
+	0x47b0acfd, 0xed93fa9b, 0xe8d3c48d, 0x283b57cc, 0xf8d56629, 0x79132e28,
+	0x785f0191, 0xed756055, 0xf7960e44, 0xe3d35e8c, 0x15056dd4, 0x88f46dba,
+	0x03a16125, 0x0564f0bd, 0xc3eb9e15, 0x3c9057a2, 0x97271aec, 0xa93a072a,
+	0x1b3f6d9b, 0x1e6321f5, 0xf59c66fb, 0x26dcf319, 0x7533d928, 0xb155fdf5,
+	0x03563482, 0x8aba3cbb, 0x28517711, 0xc20ad9f8, 0xabcc5167, 0xccad925f,
+	0x4de81751, 0x3830dc8e, 0x379d5862, 0x9320f991, 0xea7a90c2, 0xfb3e7bce,
+	0x5121ce64, 0x774fbe32, 0xa8b6e37e, 0xc3293d46, 0x48de5369, 0x6413e680,
+	0xa2ae0810, 0xdd6db224, 0x69852dfd, 0x09072166, 0xb39a460a, 0x6445c0dd,
+	0x586cdecf, 0x1c20c8ae, 0x5bbef7dd, 0x1b588d40, 0xccd2017f, 0x6bb4e3bb,
+	0xdda26a7e, 0x3a59ff45, 0x3e350a44, 0xbcb4cdd5, 0x72eacea8, 0xfa6484bb,
+	0x8d6612ae, 0xbf3c6f47, 0xd29be463, 0x542f5d9e, 0xaec2771b, 0xf64e6370,
+	0x740e0d8d, 0xe75b1357, 0xf8721671, 0xaf537d5d, 0x4040cb08, 0x4eb4e2cc,
+	0x34d2466a, 0x0115af84, 0xe1b00428, 0x95983a1d, 0x06b89fb4, 0xce6ea048,
+	0x6f3f3b82, 0x3520ab82, 0x011a1d4b, 0x277227f8, 0x611560b1, 0xe7933fdc,
+	0xbb3a792b, 0x344525bd, 0xa08839e1, 0x51ce794b, 0x2f32c9b7, 0xa01fbac9,
+	0xe01cc87e, 0xbcc7d1f6, 0xcf0111c3, 0xa1e8aac7, 0x1a908749, 0xd44fbd9a,
+	0xd0dadecb, 0xd50ada38, 0x0339c32a, 0xc6913667, 0x8df9317c, 0xe0b12b4f,
+	0xf79e59b7, 0x43f5bb3a, 0xf2d519ff, 0x27d9459c, 0xbf97222c, 0x15e6fc2a,
+	0x0f91fc71, 0x9b941525, 0xfae59361, 0xceb69ceb, 0xc2a86459, 0x12baa8d1,
+	0xb6c1075e, 0xe3056a0c, 0x10d25065, 0xcb03a442, 0xe0ec6e0e, 0x1698db3b,
+	0x4c98a0be, 0x3278e964, 0x9f1f9532, 0xe0d392df, 0xd3a0342b, 0x8971f21e,
+	0x1b0a7441, 0x4ba3348c, 0xc5be7120, 0xc37632d8, 0xdf359f8d, 0x9b992f2e,
+	0xe60b6f47, 0x0fe3f11d, 0xe54cda54, 0x1edad891, 0xce6279cf, 0xcd3e7e6f,
+	0x1618b166, 0xfd2c1d05, 0x848fd2c5, 0xf6fb2299, 0xf523f357, 0xa6327623,
+	0x93a83531, 0x56cccd02, 0xacf08162, 0x5a75ebb5, 0x6e163697, 0x88d273cc,
+	0xde966292, 0x81b949d0, 0x4c50901b, 0x71c65614, 0xe6c6c7bd, 0x327a140a,
+	0x45e1d006, 0xc3f27b9a, 0xc9aa53fd, 0x62a80f00, 0xbb25bfe2, 0x35bdd2f6,
+	0x71126905, 0xb2040222, 0xb6cbcf7c, 0xcd769c2b, 0x53113ec0, 0x1640e3d3,
+	0x38abbd60, 0x2547adf0, 0xba38209c, 0xf746ce76, 0x77afa1c5, 0x20756060,
+	0x85cbfe4e, 0x8ae88dd8, 0x7aaaf9b0, 0x4cf9aa7e, 0x1948c25c, 0x02fb8a8c,
+	0x01c36ae4, 0xd6ebe1f9, 0x90d4f869, 0xa65cdea0, 0x3f09252d, 0xc208e69f,
+	0xb74e6132, 0xce77e25b, 0x578fdfe3, 0x3ac372e6,
+}
+
+var blowfishP = [18]uint32{
+	0x243f6a88, 0x85a308d3, 0x13198a2e, 0x03707344, 0xa4093822, 0x299f31d0,
+	0x082efa98, 0xec4e6c89, 0x452821e6, 0x38d01377, 0xbe5466cf, 0x34e90c6c,
+	0xc0ac29b7, 0xc97c50dd, 0x3f84d5b5, 0xb5470917, 0x9216d5d9, 0x8979fb1b,
+}
+`
+	FormatGo = `package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+//CheckFormat returns an error when a rendered output is not well-formed "json" or "xml",
+//any other format is not checked
+func CheckFormat(format string, output []byte) error {
+	switch format {
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal(output, &v); err != nil {
+			return fmt.Errorf("format: output is not valid json: %s", err.Error())
+		}
+	case "xml":
+		d := xml.NewDecoder(bytes.NewReader(output))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("format: output is not well-formed xml: %s", err.Error())
+			}
+		}
+	}
+	return nil
+}
+`
+	LayoutGo = `package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+//ParseLayout parses the text of a template file into t. A template can extend a base template with
+//{{/* extends "base.yml" */}} at its beginning, after the front-matter or the delims header if any, the path is
+//relative to the template and an extends comment anywhere else is an error. The base template
+//is parsed first and the template can only override the blocks the base templates define, e.g.
+//{{define "resources"}}...{{end}} replaces the {{block "resources" .}}...{{end}} of the base, or add
+//blocks in its overrides for the templates extending it.
+//Empty delimiters are the default "{{" and "}}"
+func ParseLayout(t *template.Template, file, text, leftDelim, rightDelim string) error {
+	l := layout{t: t, leftDelim: leftDelim, rightDelim: rightDelim}
+	_, err := l.parse(file, text, nil)
+	return err
+}
+
+type layout struct {
+	t                     *template.Template
+	leftDelim, rightDelim string
+}
+
+//parse returns the blocks defined by the template and every base template of its chain
+func (l layout) parse(file, text string, children []string) (map[string]bool, error) {
+	for _, v := range children {
+		if v == file {
+			return nil, fmt.Errorf("layout: %s extends itself through %s", file, strings.Join(children, ", "))
+		}
+	}
+	trees, err := l.trees(file, text)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make(map[string]bool)
+	for k := range trees {
+		if k != file {
+			blocks[k] = true
+		}
+	}
+	base, ok := l.extends(text)
+	if !ok && anyExtends.MatchString(text) {
+		return nil, fmt.Errorf("layout: %s has an extends comment that is not at its beginning or not in its delimiters", file)
+	}
+	if !ok {
+		if file == l.t.Name() {
+			_, err = l.t.Parse(text)
+			return blocks, err
+		}
+		nt, err := l.t.New(file).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		_, err = l.t.AddParseTree(l.t.Name(), nt.Tree)
+		return blocks, err
+	}
+
+	if root, ok := trees[file]; ok && !parse.IsEmptyTree(root.Root) {
+		return nil, fmt.Errorf("layout: %s extends %s and can only define blocks", file, base)
+	}
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(filepath.Dir(file), base)
+	}
+	data, err := ioutil.ReadFile(base)
+	if err != nil {
+		return nil, fmt.Errorf("layout: %s", err.Error())
+	}
+	baseBlocks, err := l.parse(base, string(data), append(children, file))
+	if err != nil {
+		return nil, err
+	}
+	//a block of the template is either an override or a new block it calls, e.g. in the blocks it overrides
+	calls := make(map[string]bool)
+	for _, v := range trees {
+		templateCalls(v.Root, calls)
+	}
+	for k := range blocks {
+		if !baseBlocks[k] && !calls[k] {
+			return nil, fmt.Errorf("layout: %s overrides block %q that %s does not define", file, k, base)
+		}
+	}
+	for k := range baseBlocks {
+		blocks[k] = true
+	}
+	if file == l.t.Name() {
+		_, err = l.t.Parse(text)
+	} else {
+		_, err = l.t.New(file).Parse(text)
+	}
+	return blocks, err
+}
+
+//templateCalls adds the names of the templates called by a node
+func templateCalls(node parse.Node, calls map[string]bool) {
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		calls[n.Name] = true
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Nodes {
+			templateCalls(v, calls)
+		}
+	case *parse.IfNode:
+		templateCalls(n.List, calls)
+		templateCalls(n.ElseList, calls)
+	case *parse.RangeNode:
+		templateCalls(n.List, calls)
+		templateCalls(n.ElseList, calls)
+	case *parse.WithNode:
+		templateCalls(n.List, calls)
+		templateCalls(n.ElseList, calls)
+	}
+}
+
+//trees parses a template without checking its functions and returns its defined templates by name
+func (l layout) trees(file, text string) (map[string]*parse.Tree, error) {
+	tree := parse.New(file)
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(text, l.leftDelim, l.rightDelim, trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+//anyExtends is an extends comment anywhere in a template and in any delimiters
+var anyExtends = regexp.MustCompile(` + "`" + `/\*\s*extends\s+"[^"]+"\s*\*/` + "`" + `)
+
+//extends returns the base template named at the beginning of a template, after the other comments
+//there e.g. a delims header or the front-matter
+func (l layout) extends(text string) (string, bool) {
+	left, right := l.leftDelim, l.rightDelim
+	if left == "" {
+		left = "{{"
+	}
+	if right == "" {
+		right = "}}"
+	}
+	left, right = regexp.QuoteMeta(left), regexp.QuoteMeta(right)
+	comment := ` + "`" + `\s*` + "`" + ` + left + ` + "`" + `-?\s*/\*(?:[^*]|\*[^/])*\*/\s*-?` + "`" + ` + right
+	re := regexp.MustCompile(` + "`" + `^(?:` + "`" + ` + comment + ` + "`" + `)*?\s*` + "`" + ` + left + ` + "`" + `-?\s*/\*\s*extends\s+"([^"]+)"\s*\*/\s*-?` + "`" + ` + right)
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+`
 	MainGotempl = `package main
 import (
+    {{if and (eq .ListPipes "") (not .OutputDir)}}
     "bytes"
-    "fmt"
     "io/ioutil"
-    "os"
     "text/template"
+    {{end}}
+    "fmt"
+    "os"
+    {{if ne .Now ""}}"time"{{end}}
     )
 func checkError(err error, detail string) {
   if err != nil {
@@ -16,14 +512,36 @@ func checkError(err error, detail string) {
   }
 }
 func main() {
-  data, err := ioutil.ReadFile("{{.GoTemplate}}")
-    checkError(err, "reading template file")
-    pipes := NewPipes()
-    {{if ne .CustomPipes ""}}
-    pipes.Extend(CustomPipes())
+    pipes := goflat_NewPipes()
+    {{if ne .Now ""}}
+    now, err := time.Parse(time.RFC3339Nano, "{{.Now}}")
+    checkError(err, "parsing now")
+    pipes.SetNow(now)
     {{end}}
-    tmpl, err := template.New("").Funcs(pipes.Map).Parse(string(data))
-    checkError(err, "parsing template file")
+    {{if .AllowEnv}}
+    pipes.AllowEnv({{range .AllowEnv}}{{printf "%q" .}}, {{end}})
+    {{end}}
+    {{if .CustomPipes}}
+    overrides := []string{}
+    {{range .CustomPipesOverrides}}
+    overrides = append(overrides, {{.}}()...)
+    {{end}}
+    warnings, err := pipes.Override([]goflat_Provider{
+      {{range .CustomProviders}}
+      {Name: {{printf "%q" .Name}}, Pipes: {{.Func}}()},
+      {{end}}
+    }, overrides, "{{.PipesOverride}}")
+    checkError(err, "overriding default pipes")
+    for _, v := range warnings {
+      fmt.Fprintf(os.Stderr, "Warning: %s\n", v)
+    }
+    {{range .CustomPipesInfo}}
+    checkError(pipes.DescribeCustom({{.}}()), "describing custom pipes")
+    {{end}}
+    {{end}}
+  {{if ne .ListPipes ""}}
+    checkError(pipes.Print(os.Stdout, "{{.ListPipes}}"), "listing pipes")
+  {{else}}
     var result struct {
       {{if gt (len .GoInputs) 0}}
       {{range .GoInputs}}
@@ -36,72 +554,3418 @@ func main() {
   result.{{.StructName}} = New{{.StructName}}()
   {{end}}
   {{end}}
+  {{if .OutputDir}}
+    pipes.SetFileRoot({{printf "%q" .FileDir}}, {{printf "%q" .FileRoot}})
+    unused, err := pipes.RenderTree({{printf "%q" .OutputDir}}, {{printf "%q" .LeftDelim}}, {{printf "%q" .RightDelim}}, []goflat_Template{
+      {{range .Files}}
+      {File: {{printf "%q" .Path}}, Name: {{printf "%q" .Name}}, Copy: {{.Copy}}, LeftDelim: {{printf "%q" (or .LeftDelim $.LeftDelim)}}, RightDelim: {{printf "%q" (or .RightDelim $.RightDelim)}},
+        Partials: []string{ {{range $.Partials}}{{printf "%q" .}}, {{end}} }, Engine: {{printf "%q" (or .Engine $.Engine)}}, Format: {{printf "%q" (or .Format $.Format)}}, Strict: {{$.Strict}}},
+      {{end}}
+    }, result)
+    checkError(err, "rendering template directory")
+    for _, v := range unused {
+      fmt.Fprintf(os.Stderr, "Warning: input field %s is not referenced by any template\n", v)
+    }
+  {{else}}
+  data, err := ioutil.ReadFile("{{.GoTemplate}}")
+    checkError(err, "reading template file")
+    tmpl := template.New("{{.GoTemplate}}").Delims({{printf "%q" .LeftDelim}}, {{printf "%q" .RightDelim}}).Funcs(pipes.Map)
+    {{if .Strict}}
+    tmpl.Option("missingkey=error")
+    {{end}}
+    {{if .Partials}}
+    for _, v := range []string{ {{range .Partials}}{{printf "%q" .}}, {{end}} } {
+      partial, err := ioutil.ReadFile(v)
+      checkError(err, "reading partial file")
+      _, err = tmpl.New(v).Parse(string(partial))
+      checkError(err, "parsing partial file")
+    }
+    {{end}}
+    err = goflat_ParseLayout(tmpl, "{{.GoTemplate}}", string(data), {{printf "%q" .LeftDelim}}, {{printf "%q" .RightDelim}})
+    checkError(err, "parsing template file")
+    pipes.SetTemplate(tmpl)
+    pipes.SetFileRoot({{printf "%q" .FileDir}}, {{printf "%q" .FileRoot}})
   var output bytes.Buffer
+    {{if eq .Engine "html"}}
+    htmpl, err := pipes.HTMLTemplate(tmpl{{if .Strict}}, "missingkey=error"{{end}})
+    checkError(err, "parsing template file")
+    err = htmpl.Execute(&output, result)
+    {{else}}
     err = tmpl.Execute(&output, result)
+    {{end}}
     checkError(err, "executing template output")
+    {{if or (eq .Format "json") (eq .Format "xml")}}
+    checkError(goflat_CheckFormat("{{.Format}}", output.Bytes()), "checking template output")
+    {{end}}
+    {{if .Strict}}
+    checkError(goflat_CheckOutput(output.Bytes()), "executing template output")
+    for _, v := range goflat_UnusedFields(tmpl, result) {
+      fmt.Fprintf(os.Stderr, "Warning: input field %s is not referenced by the template\n", v)
+    }
+    {{end}}
     fmt.Println(string(output.Bytes()))
+  {{end}}
+  {{end}}
 }
 `
 	PipesGo = `package runtime
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
 	"reflect"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
+)
+
+const (
+	SourceDefault = "default"
+	SourceCustom  = "custom"
+
+	//OverrideAllow silently replaces the default pipes
+	OverrideAllow = "allow"
+	//OverrideWarn reports the undeclared overrides of the default pipes
+	OverrideWarn = "warn"
+	//OverrideError fails on the undeclared overrides of the default pipes
+	OverrideError = "error"
 )
 
+//PipeInfo describes a pipe, Name, Signature and Source are filled in when the pipe is added
+type PipeInfo struct {
+	Name        string   ` + "`" + `json:"name"` + "`" + `
+	Signature   string   ` + "`" + `json:"signature"` + "`" + `
+	Description string   ` + "`" + `json:"description,omitempty"` + "`" + `
+	Examples    []string ` + "`" + `json:"examples,omitempty"` + "`" + `
+	Deprecated  string   ` + "`" + `json:"deprecated,omitempty"` + "`" + `
+	Source      string   ` + "`" + `json:"source"` + "`" + `
+	Provider    string   ` + "`" + `json:"provider,omitempty"` + "`" + `
+	Overrides   bool     ` + "`" + `json:"overrides,omitempty"` + "`" + `
+}
+
+//Provider is a named function of the custom pipes
+type Provider struct {
+	Name  string
+	Pipes template.FuncMap
+}
+
 type Pipes struct {
-	Map template.FuncMap
+	Map  template.FuncMap
+	Info map[string]PipeInfo
+
+	regexps regexCache
+	clock   time.Time
+	tmpl    *template.Template
+	depth   int
+
+	html        *htmltemplate.Template
+	htmlOptions []string
+
+	fileDir, fileRoot string
+	envAllow          []string
 }
 
+//Extend adds custom pipes, overriding the default pipes with the same name
 func (p *Pipes) Extend(fm template.FuncMap) {
+	p.add(SourceCustom, fm, nil)
+}
+
+//Override merges the custom pipes of the providers in order and applies the override policy to the default pipes
+//they replace, declared lists the intended overrides. A later provider replacing the pipe of an earlier provider is
+//reported the same way. The returned warnings are the problems found under OverrideWarn
+func (p *Pipes) Override(providers []Provider, declared []string, policy string) ([]string, error) {
+	if policy != OverrideAllow && policy != OverrideWarn && policy != OverrideError {
+		return nil, fmt.Errorf("unknown override policy %q (allow, warn or error)", policy)
+	}
+	intended := make(map[string]bool, len(declared))
+	for _, v := range declared {
+		intended[v] = true
+	}
+	var problems []string
+	merged := make(map[string]string)
+	for _, provider := range providers {
+		for _, k := range sortedFuncs(provider.Pipes) {
+			if earlier, ok := merged[k]; ok {
+				problems = append(problems, fmt.Sprintf("pipe %q of %s replaces the one of %s", k, provider.Name, earlier))
+			} else if i, ok := p.Info[k]; ok && i.Source == SourceDefault && !intended[k] {
+				problems = append(problems, fmt.Sprintf("pipe %q of %s overrides a default pipe without being declared in CustomPipesOverrides", k, provider.Name))
+			}
+			merged[k] = provider.Name
+		}
+	}
+	for _, v := range declared {
+		if i, ok := p.Info[v]; !ok || i.Source != SourceDefault {
+			problems = append(problems, fmt.Sprintf("pipe %q is declared as an override but there is no default pipe to override", v))
+		} else if _, ok := merged[v]; !ok {
+			problems = append(problems, fmt.Sprintf("pipe %q is declared as an override but is not defined", v))
+		}
+	}
+	if len(problems) > 0 && policy == OverrideError {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	for _, provider := range providers {
+		p.add(SourceCustom, provider.Pipes, nil)
+		for k := range provider.Pipes {
+			i := p.Info[k]
+			i.Provider = provider.Name
+			p.Info[k] = i
+		}
+	}
+	if policy == OverrideWarn {
+		return problems, nil
+	}
+	return nil, nil
+}
+
+//Overridden returns the sorted names of the default pipes replaced by custom pipes
+func (p *Pipes) Overridden() []string {
+	out := []string{}
+	for _, v := range p.List() {
+		if v.Overrides {
+			out = append(out, v.Name)
+		}
+	}
+	return out
+}
+
+//Describe adds the description, examples and deprecation of already defined pipes
+func (p *Pipes) Describe(info map[string]PipeInfo) error {
+	for k, v := range info {
+		i, ok := p.Info[k]
+		if !ok {
+			return fmt.Errorf("cannot describe undefined pipe %q", k)
+		}
+		i.Description, i.Examples, i.Deprecated = v.Description, v.Examples, v.Deprecated
+		p.Info[k] = i
+	}
+	return nil
+}
+
+//DescribeCustom adds the descriptions of CustomPipesInfo, a plain map so that the custom pipes build without the runtime
+func (p *Pipes) DescribeCustom(info map[string]struct {
+	Description string
+	Examples    []string
+	Deprecated  string
+}) error {
+	out := make(map[string]PipeInfo, len(info))
+	for k, v := range info {
+		out[k] = PipeInfo{Description: v.Description, Examples: v.Examples, Deprecated: v.Deprecated}
+	}
+	return p.Describe(out)
+}
+
+//List returns the information of every pipe sorted by name
+func (p *Pipes) List() []PipeInfo {
+	out := make([]PipeInfo, 0, len(p.Info))
+	for _, v := range p.Info {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+//Print writes the list of pipes in "text" or "json" format
+func (p *Pipes) Print(w io.Writer, format string) error {
+	list := p.List()
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	case "text":
+		for _, v := range list {
+			source := v.Source
+			if v.Provider != "" {
+				source += " " + v.Provider
+			}
+			if v.Overrides {
+				source += ", overrides " + SourceDefault
+			}
+			fmt.Fprintf(w, "%s %s [%s]\n", v.Name, v.Signature, source)
+			if v.Deprecated != "" {
+				fmt.Fprintf(w, "    DEPRECATED: %s\n", v.Deprecated)
+			}
+			if v.Description != "" {
+				fmt.Fprintf(w, "    %s\n", v.Description)
+			}
+			for _, e := range v.Examples {
+				fmt.Fprintf(w, "    e.g. %s\n", e)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q (text or json)", format)
+}
+
+func (p *Pipes) add(source string, fm template.FuncMap, info map[string]PipeInfo) {
 	for k, v := range fm {
+		i := info[k]
+		i.Name, i.Signature, i.Source = k, reflect.TypeOf(v).String(), source
+		if old, ok := p.Info[k]; ok && source == SourceCustom {
+			i.Overrides = old.Source == SourceDefault || old.Overrides
+		}
 		p.Map[k] = v
+		p.Info[k] = i
 	}
 }
 
+func sortedFuncs(fm template.FuncMap) []string {
+	keys := make([]string, 0, len(fm))
+	for k := range fm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func NewPipes() *Pipes {
-	return &Pipes{
-		Map: template.FuncMap{
-			"join": func(sep string, a []string) (string, error) {
-				return strings.Join(a, sep), nil
-			},
-			//e.g. map "Name,Age,Job" "|"  => "[John|25|Painter Jane|21|Teacher]"
-			"map": func(f, sep string, a interface{}) ([]string, error) {
-				fields := strings.Split(f, ",")
-				reflectedArray := reflect.ValueOf(a)
-				out := make([]string, reflectedArray.Len())
-				i := 0
-				for i < len(out) {
-					v := reflectedArray.Index(i)
-					row := make([]string, len(fields))
-					for k, field := range fields {
-						row[k] = v.FieldByName(field).String()
+	p := &Pipes{
+		Map:  template.FuncMap{},
+		Info: make(map[string]PipeInfo),
+	}
+	p.add(SourceDefault, basicPipes(), basicPipesInfo)
+	p.add(SourceDefault, stringPipes(), stringPipesInfo)
+	p.add(SourceDefault, convertPipes(), convertPipesInfo)
+	p.add(SourceDefault, missingPipes(), missingPipesInfo)
+	p.add(SourceDefault, cryptoPipes(), cryptoPipesInfo)
+	p.add(SourceDefault, uuidPipes(), uuidPipesInfo)
+	p.add(SourceDefault, p.regexPipes(), regexPipesInfo)
+	p.add(SourceDefault, p.datePipes(), datePipesInfo)
+	p.add(SourceDefault, networkPipes(), networkPipesInfo)
+	p.add(SourceDefault, mathPipes(), mathPipesInfo)
+	p.add(SourceDefault, semverPipes(), semverPipesInfo)
+	p.add(SourceDefault, pathPipes(), pathPipesInfo)
+	p.add(SourceDefault, dictPipes(), dictPipesInfo)
+	p.add(SourceDefault, p.includePipes(), includePipesInfo)
+	p.add(SourceDefault, htmlPipes(), htmlPipesInfo)
+	p.add(SourceDefault, p.filePipes(), filePipesInfo)
+	p.add(SourceDefault, p.envPipes(), envPipesInfo)
+	return p
+}
+
+//basicPipes are the helper functions for lists and strings, the piped values are converted
+//with toString and toStrings so that e.g. a []interface{} of decoded JSON can be joined
+func basicPipes() template.FuncMap {
+	return template.FuncMap{
+		"join": func(sep string, a interface{}) (string, error) {
+			return strings.Join(toStrings(a), sep), nil
+		},
+		//e.g. map "Name,Age,Job" "|"  => "[John|25|Painter Jane|21|Teacher]"
+		"map": func(f, sep string, a interface{}) ([]string, error) {
+			fields := strings.Split(f, ",")
+			reflectedArray := reflect.ValueOf(indirect(a))
+			if reflectedArray.Kind() != reflect.Slice && reflectedArray.Kind() != reflect.Array {
+				return nil, fmt.Errorf("map: cannot iterate over %T", a)
+			}
+			out := make([]string, reflectedArray.Len())
+			for i := range out {
+				row := make([]string, len(fields))
+				for k, field := range fields {
+					v, err := fieldOf(reflectedArray.Index(i), field)
+					if err != nil {
+						return nil, err
 					}
-					out[i] = strings.Join(row, sep)
-					i++
-				}
-				return out, nil
-			},
-			"replace": func(old, new, s string) (string, error) {
-				//replace all occurrences of a value
-				return strings.Replace(s, old, new, -1), nil
-			},
-			"split": func(sep, s string) ([]string, error) {
-				s = strings.TrimSpace(s)
-				if s == "" {
-					return []string{}, nil
+					row[k] = toString(v)
 				}
-				return strings.Split(s, sep), nil
-			},
-			"toUpper": func(s string) (string, error) {
-				return strings.ToUpper(s), nil
-			},
-			"toLower": func(s string) (string, error) {
-				return strings.ToLower(s), nil
-			},
+				out[i] = strings.Join(row, sep)
+			}
+			return out, nil
+		},
+		"replace": func(old, new string, s interface{}) (string, error) {
+			//replace all occurrences of a value
+			return strings.Replace(toString(s), old, new, -1), nil
+		},
+		"split": func(sep string, v interface{}) ([]string, error) {
+			s := strings.TrimSpace(toString(v))
+			if s == "" {
+				return []string{}, nil
+			}
+			return strings.Split(s, sep), nil
+		},
+		"toUpper": func(s interface{}) (string, error) {
+			return strings.ToUpper(toString(s)), nil
+		},
+		"toLower": func(s interface{}) (string, error) {
+			return strings.ToLower(toString(s)), nil
+		},
+	}
+}
+
+//fieldOf returns the field of a struct or the key of a map with string keys
+func fieldOf(v reflect.Value, name string) (interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			return nil, fmt.Errorf("map: %s has no field %q", v.Type(), name)
+		}
+		return f.Interface(), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			f := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !f.IsValid() {
+				return nil, nil
+			}
+			return f.Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("map: cannot read field %q of %s", name, v.Type())
+}
+
+var basicPipesInfo = map[string]PipeInfo{
+	"join": {
+		Description: "Joins a list of strings with a separator.",
+		Examples:    []string{` + "`" + `{{.List | join ","}}` + "`" + `},
+	},
+	"map": {
+		Description: "Joins the comma separated fields of every object in a list with a separator.",
+		Examples:    []string{` + "`" + `{{.ListOfObjects | map "Name,Age" ","}}` + "`" + `},
+	},
+	"replace": {
+		Description: "Replaces all occurrences of a value.",
+		Examples:    []string{` + "`" + `{{.StringValue | replace "," " "}}` + "`" + `},
+	},
+	"split": {
+		Description: "Splits a string by a separator, an empty string is an empty list.",
+		Examples:    []string{` + "`" + `{{.StringValue | split ","}}` + "`" + `},
+	},
+	"toLower": {
+		Description: "Converts a string to lower case.",
+		Examples:    []string{` + "`" + `{{.Field | toLower}}` + "`" + `},
+	},
+	"toUpper": {
+		Description: "Converts a string to upper case.",
+		Examples:    []string{` + "`" + `{{.Field | toUpper}}` + "`" + `},
+	},
+}
+`
+	PipesConvertGo = `package runtime
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//convertPipes are helper functions for converting and inspecting values e.g. the
+//[]interface{} and float64 values of decoded JSON
+func convertPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. toString 8080  => "8080", nil is an empty string
+		"toString": func(v interface{}) (string, error) {
+			return toString(v), nil
+		},
+		//e.g. toInt "8080" or toInt 8080.0  => 8080
+		"toInt": func(v interface{}) (int, error) {
+			return toInt(v)
+		},
+		"toFloat": func(v interface{}) (float64, error) {
+			return toFloat64(indirect(v))
+		},
+		//e.g. toBool "yes" is an error, toBool "true", toBool "1" and toBool 1  => true
+		"toBool": func(v interface{}) (bool, error) {
+			return toBool(v)
+		},
+		//e.g. toStrings .Ports  => ["8080" "8443"]
+		"toStrings": func(v interface{}) ([]string, error) {
+			return toStrings(v), nil
+		},
+		//e.g. kindOf .Ports  => "slice"
+		"kindOf": func(v interface{}) (string, error) {
+			return reflect.ValueOf(v).Kind().String(), nil
+		},
+		//e.g. typeOf .Ports  => "[]int"
+		"typeOf": func(v interface{}) (string, error) {
+			return fmt.Sprintf("%T", v), nil
+		},
+		"kindIs": func(kind string, v interface{}) (bool, error) {
+			return reflect.ValueOf(v).Kind().String() == kind, nil
 		},
 	}
 }
+
+//indirect returns the value a pointer points to, nil for a nil pointer
+func indirect(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+//toString converts any value to its string form, nil is an empty string
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case fmt.Stringer:
+		return s.String()
+	case error:
+		return s.Error()
+	}
+	v = indirect(v)
+	if v == nil {
+		return ""
+	}
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+//toStrings converts every element of a slice or an array to a string, any other value is a list of one string
+func toStrings(v interface{}) []string {
+	v = indirect(v)
+	if v == nil {
+		return []string{}
+	}
+	if s, ok := v.([]string); ok {
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []string{toString(v)}
+	}
+	out := make([]string, rv.Len())
+	for i := range out {
+		out[i] = toString(rv.Index(i).Interface())
+	}
+	return out
+}
+
+func toInt(v interface{}) (int, error) {
+	v = indirect(v)
+	switch b := v.(type) {
+	case nil:
+		return 0, nil
+	case bool:
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		s := strings.TrimSpace(b)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			v = n
+		} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+			v = f
+		} else {
+			return 0, fmt.Errorf("cannot convert %q to int", b)
+		}
+	}
+	n, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt || n < math.MinInt {
+		return 0, errOverflow
+	}
+	return int(n), nil
+}
+
+func toBool(v interface{}) (bool, error) {
+	v = indirect(v)
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(b))
+	}
+	f, err := toFloat64(v)
+	if err != nil {
+		return false, fmt.Errorf("cannot convert %T to bool", v)
+	}
+	return f != 0, nil
+}
+
+var convertPipesInfo = map[string]PipeInfo{
+	"toString": {
+		Description: "Converts a value to a string, nil is an empty string.",
+		Examples:    []string{` + "`" + `{{.Port | toString}}` + "`" + `},
+	},
+	"toInt": {
+		Description: "Converts a number, a numeric string or a bool to an integer.",
+		Examples:    []string{` + "`" + `{{.Port | toInt}}` + "`" + `},
+	},
+	"toFloat": {
+		Description: "Converts a number or a numeric string to a float.",
+		Examples:    []string{` + "`" + `{{.Ratio | toFloat}}` + "`" + `},
+	},
+	"toBool": {
+		Description: "Converts a bool, a number or a string such as \"true\" or \"0\" to a bool.",
+		Examples:    []string{` + "`" + `{{if .Enabled | toBool}}...{{end}}` + "`" + `},
+	},
+	"toStrings": {
+		Description: "Converts every element of a list to a string.",
+		Examples:    []string{` + "`" + `{{.Ports | toStrings | join ","}}` + "`" + `},
+	},
+	"kindOf": {
+		Description: "Returns the kind of a value e.g. string, int, slice, map or struct.",
+		Examples:    []string{` + "`" + `{{.Ports | kindOf}}` + "`" + `},
+	},
+	"typeOf": {
+		Description: "Returns the Go type of a value.",
+		Examples:    []string{` + "`" + `{{.Ports | typeOf}}` + "`" + `},
+	},
+	"kindIs": {
+		Description: "Reports whether a value is of the kind.",
+		Examples:    []string{` + "`" + `{{if .Ports | kindIs "slice"}}...{{end}}` + "`" + `},
+	},
+}
+`
+	PipesCryptoGo = `package runtime
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"text/template"
+)
+
+//cryptoPipes are helper functions for checksums and derived credentials
+func cryptoPipes() template.FuncMap {
+	return template.FuncMap{
+		"md5": func(v interface{}) (string, error) {
+			sum := md5.Sum([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		"sha1": func(v interface{}) (string, error) {
+			sum := sha1.Sum([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		"sha256": func(v interface{}) (string, error) {
+			sum := sha256.Sum256([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		"sha512": func(v interface{}) (string, error) {
+			sum := sha512.Sum512([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		//e.g. hmacSha256 .Private.Secret .Payload  => hex encoded signature
+		"hmacSha256": func(key string, v interface{}) (string, error) {
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write([]byte(toString(v)))
+			return hex.EncodeToString(mac.Sum(nil)), nil
+		},
+		"bcrypt": func(v interface{}) (string, error) {
+			return bcryptHash(toString(v))
+		},
+		//e.g. htpasswd "admin" .Private.Password  => "admin:$2a$10$..."
+		"htpasswd": func(user string, v interface{}) (string, error) {
+			hash, err := bcryptHash(toString(v))
+			if err != nil {
+				return "", err
+			}
+			return user + ":" + hash, nil
+		},
+		//e.g. derivePassword 16 "db-admin" .Private.Secret  => the same 16 characters for every render
+		"derivePassword": func(length int, label string, v interface{}) (string, error) {
+			return derivePassword(length, label, toString(v))
+		},
+	}
+}
+
+const (
+	derivePasswordChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	derivePasswordMax   = 1024
+)
+
+//derivePassword deterministically expands HMAC-SHA256(master, label) into an alphanumeric password
+func derivePassword(length int, label, master string) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("derivePassword: invalid length %d", length)
+	}
+	if length > derivePasswordMax {
+		return "", fmt.Errorf("derivePassword: length %d exceeds %d", length, derivePasswordMax)
+	}
+	out := make([]byte, 0, length)
+	counter := uint32(0)
+	for len(out) < length {
+		mac := hmac.New(sha256.New, []byte(master))
+		binary.Write(mac, binary.BigEndian, counter)
+		mac.Write([]byte(label))
+		for _, b := range mac.Sum(nil) {
+			//skip bytes that would bias the distribution towards the first characters
+			if int(b) >= 256-256%len(derivePasswordChars) {
+				continue
+			}
+			out = append(out, derivePasswordChars[int(b)%len(derivePasswordChars)])
+			if len(out) == length {
+				break
+			}
+		}
+		counter++
+	}
+	return string(out), nil
+}
+
+var cryptoPipesInfo = map[string]PipeInfo{
+	"md5": {
+		Description: "Returns the hex encoded MD5 checksum.",
+		Examples:    []string{` + "`" + `{{.Private.Cert | md5}}` + "`" + `},
+	},
+	"sha1": {
+		Description: "Returns the hex encoded SHA-1 checksum.",
+		Examples:    []string{` + "`" + `{{.Private.Cert | sha1}}` + "`" + `},
+	},
+	"sha256": {
+		Description: "Returns the hex encoded SHA-256 checksum.",
+		Examples:    []string{` + "`" + `{{.Private.Cert | sha256}}` + "`" + `},
+	},
+	"sha512": {
+		Description: "Returns the hex encoded SHA-512 checksum.",
+		Examples:    []string{` + "`" + `{{.Private.Cert | sha512}}` + "`" + `},
+	},
+	"hmacSha256": {
+		Description: "Returns the hex encoded HMAC-SHA256 signature with the key.",
+		Examples:    []string{` + "`" + `{{.Payload | hmacSha256 .Private.Secret}}` + "`" + `},
+	},
+	"bcrypt": {
+		Description: "Returns the bcrypt hash of the password with a random salt, it changes on every render.",
+		Examples:    []string{` + "`" + `{{.Private.Password | bcrypt}}` + "`" + `},
+	},
+	"htpasswd": {
+		Description: "Returns an htpasswd entry of the user with the bcrypt hash of the password.",
+		Examples:    []string{` + "`" + `{{.Private.Password | htpasswd "admin"}}` + "`" + `},
+	},
+	"derivePassword": {
+		Description: "Derives an alphanumeric password of the length (at most 1024) from a master secret and a label, the same inputs derive the same password.",
+		Examples:    []string{` + "`" + `{{.Private.Secret | derivePassword 16 "db-admin"}}` + "`" + `},
+	},
+}
 `
+	PipesDateGo = `package runtime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"text/template"
+	"time"
 )
+
+//SetNow pins the clock used by the date pipes so that renders are reproducible
+func (p *Pipes) SetNow(t time.Time) {
+	p.clock = t
+}
+
+func (p *Pipes) now() time.Time {
+	if p.clock.IsZero() {
+		return time.Now()
+	}
+	return p.clock
+}
+
+//datePipes are helper functions for timestamps and durations
+func (p *Pipes) datePipes() template.FuncMap {
+	return template.FuncMap{
+		"now": func() (time.Time, error) {
+			return p.now(), nil
+		},
+		//e.g. date "2006-01-02" now  => "2016-03-20"
+		"date": func(layout string, v interface{}) (string, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return "", err
+			}
+			return t.Format(layout), nil
+		},
+		//e.g. dateModify "+24h" now  => tomorrow at the same time
+		"dateModify": func(d string, v interface{}) (time.Time, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return time.Time{}, err
+			}
+			duration, err := time.ParseDuration(d)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return t.Add(duration), nil
+		},
+		"toUnix": func(v interface{}) (int64, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return 0, err
+			}
+			return t.Unix(), nil
+		},
+		//e.g. duration 95  => 1m35s, duration "1h30m"  => 1h30m0s
+		"duration": func(v interface{}) (time.Duration, error) {
+			return toDuration(v)
+		},
+		//e.g. durationRound "49h10m"  => "2d"; a time is rounded as the duration since now
+		"durationRound": func(v interface{}) (string, error) {
+			var d time.Duration
+			if t, ok := v.(time.Time); ok {
+				d = p.now().Sub(t)
+			} else {
+				var err error
+				if d, err = toDuration(v); err != nil {
+					return "", err
+				}
+			}
+			return roundDuration(d), nil
+		},
+	}
+}
+
+//toTime accepts a time.Time, unix seconds or a RFC3339 string, unix seconds are in UTC
+//so that a render does not depend on the local time zone
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		return *t, nil
+	case int:
+		return time.Unix(int64(t), 0).UTC(), nil
+	case int64:
+		return time.Unix(t, 0).UTC(), nil
+	case float64:
+		//numbers decoded from JSON are float64
+		sec := math.Floor(t)
+		return time.Unix(int64(sec), int64((t-sec)*float64(time.Second))).UTC(), nil
+	case string:
+		return time.Parse(time.RFC3339, t)
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to time", v)
+}
+
+//toDuration accepts a time.Duration, seconds or a duration string e.g. "1h30m"
+func toDuration(v interface{}) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case int:
+		return time.Duration(d) * time.Second, nil
+	case int64:
+		return time.Duration(d) * time.Second, nil
+	case float64:
+		return time.Duration(d * float64(time.Second)), nil
+	case string:
+		if s, err := strconv.ParseInt(d, 10, 64); err == nil {
+			return time.Duration(s) * time.Second, nil
+		}
+		return time.ParseDuration(d)
+	}
+	return 0, fmt.Errorf("cannot convert %T to duration", v)
+}
+
+//roundDuration keeps only the most significant unit of a duration
+func roundDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	const day = 24 * time.Hour
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{365 * day, "y"},
+		{30 * day, "mo"},
+		{day, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, u := range units {
+		if d >= u.size {
+			return fmt.Sprintf("%s%d%s", sign, d/u.size, u.name)
+		}
+	}
+	return "0s"
+}
+
+var datePipesInfo = map[string]PipeInfo{
+	"now": {
+		Description: "Returns the current time or the pinned clock.",
+		Examples:    []string{` + "`" + `{{now | date "2006-01-02"}}` + "`" + `},
+	},
+	"date": {
+		Description: "Formats a time, unix seconds in UTC or a RFC3339 string with a Go layout.",
+		Examples:    []string{` + "`" + `{{.Created | date "Jan 2 2006"}}` + "`" + `},
+	},
+	"dateModify": {
+		Description: "Adds a duration to a time.",
+		Examples:    []string{` + "`" + `{{now | dateModify "+24h"}}` + "`" + `},
+	},
+	"toUnix": {
+		Description: "Returns the unix seconds of a time.",
+		Examples:    []string{` + "`" + `{{now | toUnix}}` + "`" + `},
+	},
+	"duration": {
+		Description: "Converts seconds or a duration string to a duration.",
+		Examples:    []string{` + "`" + `{{95 | duration}}` + "`" + `, ` + "`" + `{{"1h30m" | duration}}` + "`" + `},
+	},
+	"durationRound": {
+		Description: "Rounds a duration, or the time since a time, to its most significant unit.",
+		Examples:    []string{` + "`" + `{{"49h10m" | durationRound}}` + "`" + `},
+	},
+}
+`
+	PipesDictGo = `package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"text/template"
+)
+
+//dictPipes are helper functions for building and reading dicts, every pipe accepts
+//a map with string keys or a struct and returns a new map[string]interface{}
+func dictPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. template "job" (dict "Repo" . "Private" $global.Private)
+		"dict": func(a ...interface{}) (map[string]interface{}, error) {
+			if len(a)%2 != 0 {
+				return nil, fmt.Errorf("dict: odd number of args: %d", len(a))
+			}
+			out := make(map[string]interface{}, len(a)/2)
+			for i := 0; i < len(a); i += 2 {
+				key, ok := a[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("dict: key %v is %T, not a string", a[i], a[i])
+				}
+				out[key] = a[i+1]
+			}
+			return out, nil
+		},
+		"list": func(a ...interface{}) ([]interface{}, error) {
+			return append([]interface{}{}, a...), nil
+		},
+		//e.g. set "Port" 8080 .Config
+		"set": func(key string, value, d interface{}) (map[string]interface{}, error) {
+			out, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = value
+			return out, nil
+		},
+		"unset": func(key string, d interface{}) (map[string]interface{}, error) {
+			out, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			delete(out, key)
+			return out, nil
+		},
+		"keys": func(d interface{}) ([]string, error) {
+			m, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			return sortedKeys(m), nil
+		},
+		//values are ordered by their sorted keys
+		"values": func(d interface{}) ([]interface{}, error) {
+			m, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			out := []interface{}{}
+			for _, k := range sortedKeys(m) {
+				out = append(out, m[k])
+			}
+			return out, nil
+		},
+		"hasKey": func(key string, d interface{}) (bool, error) {
+			m, err := toDict(d)
+			if err != nil {
+				return false, err
+			}
+			_, ok := m[key]
+			return ok, nil
+		},
+		//e.g. get "Port" .Config or get "Port" 8080 .Config  => 8080 when "Port" is missing
+		"get": func(key string, a ...interface{}) (interface{}, error) {
+			if len(a) == 0 || len(a) > 2 {
+				return nil, fmt.Errorf("get: wrong number of args: got %d want 2 or 3", len(a)+1)
+			}
+			m, err := toDict(a[len(a)-1])
+			if err != nil {
+				return nil, err
+			}
+			if v, ok := m[key]; ok {
+				return v, nil
+			}
+			if len(a) == 2 {
+				return a[0], nil
+			}
+			return nil, nil
+		},
+		//e.g. merge .Defaults .Overrides  => the later dicts override the earlier keys
+		"merge": func(dicts ...interface{}) (map[string]interface{}, error) {
+			return mergeDicts(dicts, false)
+		},
+		//deepMerge also merges the nested dicts
+		"deepMerge": func(dicts ...interface{}) (map[string]interface{}, error) {
+			return mergeDicts(dicts, true)
+		},
+		//e.g. pick "Password" "Secret" .Private
+		"pick": func(a ...interface{}) (map[string]interface{}, error) {
+			m, keys, err := dictAndKeys("pick", a)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[string]interface{})
+			for _, k := range keys {
+				if v, ok := m[k]; ok {
+					out[k] = v
+				}
+			}
+			return out, nil
+		},
+		"omit": func(a ...interface{}) (map[string]interface{}, error) {
+			m, keys, err := dictAndKeys("omit", a)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				delete(m, k)
+			}
+			return m, nil
+		},
+	}
+}
+
+//toDict copies a map with string keys or the exported fields of a struct into a new map
+func toDict(d interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(d)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return map[string]interface{}{}, nil
+		}
+		rv = rv.Elem()
+	}
+	out := make(map[string]interface{})
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot use %T as a dict, keys are not strings", d)
+		}
+		for _, k := range rv.MapKeys() {
+			out[k.String()] = rv.MapIndex(k).Interface()
+		}
+		return out, nil
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			out[t.Field(i).Name] = rv.Field(i).Interface()
+		}
+		return out, nil
+	case reflect.Invalid:
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot use %T as a dict", d)
+}
+
+func isDict(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Struct || (rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergeDicts(dicts []interface{}, deep bool) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	for _, d := range dicts {
+		m, err := toDict(d)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range m {
+			if deep && isDict(v) && isDict(out[k]) {
+				merged, err := mergeDicts([]interface{}{out[k], v}, true)
+				if err != nil {
+					return nil, err
+				}
+				v = merged
+			}
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+//dictAndKeys splits the args of pick and omit into the piped dict and its string keys
+func dictAndKeys(name string, a []interface{}) (map[string]interface{}, []string, error) {
+	if len(a) == 0 {
+		return nil, nil, errors.New(name + ": missing dict")
+	}
+	m, err := toDict(a[len(a)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, len(a)-1)
+	for k, v := range a[:len(a)-1] {
+		s, ok := v.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: key %v is %T, not a string", name, v, v)
+		}
+		keys[k] = s
+	}
+	return m, keys, nil
+}
+
+var dictPipesInfo = map[string]PipeInfo{
+	"dict": {
+		Description: "Builds a dict from key and value pairs.",
+		Examples:    []string{` + "`" + `{{template "job" (dict "Repo" . "Private" $global.Private)}}` + "`" + `},
+	},
+	"list": {
+		Description: "Builds a list from the values.",
+		Examples:    []string{` + "`" + `{{range list "repo1" "repo2"}}...{{end}}` + "`" + `},
+	},
+	"set": {
+		Description: "Returns a dict with the key set to the value.",
+		Examples:    []string{` + "`" + `{{.Config | set "Port" 8080}}` + "`" + `},
+	},
+	"unset": {
+		Description: "Returns a dict without the key.",
+		Examples:    []string{` + "`" + `{{.Config | unset "Debug"}}` + "`" + `},
+	},
+	"keys": {
+		Description: "Returns the sorted keys of a dict.",
+		Examples:    []string{` + "`" + `{{.Config | keys}}` + "`" + `},
+	},
+	"values": {
+		Description: "Returns the values of a dict ordered by their sorted keys.",
+		Examples:    []string{` + "`" + `{{.Config | values}}` + "`" + `},
+	},
+	"hasKey": {
+		Description: "Reports whether a dict has the key.",
+		Examples:    []string{` + "`" + `{{if .Config | hasKey "Port"}}...{{end}}` + "`" + `},
+	},
+	"get": {
+		Description: "Returns the value of the key or the optional default.",
+		Examples:    []string{` + "`" + `{{.Config | get "Port" 8080}}` + "`" + `},
+	},
+	"merge": {
+		Description: "Merges dicts, the later dicts override the earlier keys.",
+		Examples:    []string{` + "`" + `{{.Overrides | merge .Defaults}}` + "`" + `},
+	},
+	"deepMerge": {
+		Description: "Merges dicts and their nested dicts, the later dicts override the earlier keys.",
+		Examples:    []string{` + "`" + `{{.Overrides | deepMerge .Defaults}}` + "`" + `},
+	},
+	"pick": {
+		Description: "Returns a dict with only the keys.",
+		Examples:    []string{` + "`" + `{{.Private | pick "Password" "Secret"}}` + "`" + `},
+	},
+	"omit": {
+		Description: "Returns a dict without the keys.",
+		Examples:    []string{` + "`" + `{{.Private | omit "Password"}}` + "`" + `},
+	},
+}
+`
+	PipesEnvGo = `package runtime
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/template"
+)
+
+//AllowEnv sets the patterns of the environment variables the env pipes can read e.g. "CI_*",
+//every variable is refused without a pattern
+func (p *Pipes) AllowEnv(patterns ...string) {
+	p.envAllow = patterns
+}
+
+//envPipes are helper functions for reading the allowed environment variables
+func (p *Pipes) envPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. env "CI_BRANCH"  => "" when the variable is not set
+		"env": func(name string) (string, error) {
+			if err := p.allowedEnv("env", name); err != nil {
+				return "", err
+			}
+			return os.Getenv(name), nil
+		},
+		"requiredEnv": func(name string) (string, error) {
+			if err := p.allowedEnv("requiredEnv", name); err != nil {
+				return "", err
+			}
+			v := os.Getenv(name)
+			if v == "" {
+				return "", fmt.Errorf("requiredEnv: %s is not set", name)
+			}
+			return v, nil
+		},
+		//e.g. expandEnv "https://${CI_HOST}/$CI_REPO"
+		"expandEnv": func(s string) (string, error) {
+			var err error
+			out := os.Expand(s, func(name string) string {
+				if e := p.allowedEnv("expandEnv", name); e != nil {
+					if err == nil {
+						err = e
+					}
+					return ""
+				}
+				return os.Getenv(name)
+			})
+			return out, err
+		},
+	}
+}
+
+func (p *Pipes) allowedEnv(pipe, name string) error {
+	for _, v := range p.envAllow {
+		if ok, _ := path.Match(v, name); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %s is not allowed, see --allow-env", pipe, name)
+}
+
+var envPipesInfo = map[string]PipeInfo{
+	"env": {
+		Description: "Returns an allowed environment variable, empty when it is not set.",
+		Examples:    []string{` + "`" + `{{env "CI_BRANCH" | default "master"}}` + "`" + `},
+	},
+	"requiredEnv": {
+		Description: "Returns an allowed environment variable or fails when it is not set or empty.",
+		Examples:    []string{` + "`" + `{{requiredEnv "DEPLOY_TOKEN"}}` + "`" + `},
+	},
+	"expandEnv": {
+		Description: "Replaces $VAR and ${VAR} of allowed environment variables in a string.",
+		Examples:    []string{` + "`" + `{{.Repo | expandEnv}}` + "`" + `},
+	},
+}
+`
+	PipesFileGo = `package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//SetFileRoot sets the directory the file pipes resolve the relative paths from, usually the directory
+//of the template, and the root directory they cannot read outside of
+func (p *Pipes) SetFileRoot(dir, root string) {
+	p.fileDir, p.fileRoot = dir, root
+}
+
+//filePipes are helper functions for reading the files next to the template
+func (p *Pipes) filePipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. readFile "certs/ca.pem"
+		"readFile": func(name string) (string, error) {
+			file, err := p.resolve("readFile", name)
+			if err != nil {
+				return "", err
+			}
+			data, err := ioutil.ReadFile(file)
+			return string(data), err
+		},
+		//e.g. readLines "hosts.txt"  => a line without its line ending for every line
+		"readLines": func(name string) ([]string, error) {
+			file, err := p.resolve("readLines", name)
+			if err != nil {
+				return nil, err
+			}
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) == 0 {
+				return []string{}, nil
+			}
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			for k, v := range lines {
+				lines[k] = strings.TrimSuffix(v, "\r")
+			}
+			return lines, nil
+		},
+		//e.g. fileGlob "scripts/*.sh"  => the sorted matches relative to the template directory, without the
+		//matches linking outside of the root directory
+		"fileGlob": func(pattern string) ([]string, error) {
+			dir, root, err := p.fileDirs()
+			if err != nil {
+				return nil, err
+			}
+			abs := pattern
+			if !filepath.IsAbs(abs) {
+				abs = filepath.Join(dir, abs)
+			}
+			if !insideDir(root, filepath.Clean(abs)) {
+				return nil, fmt.Errorf("fileGlob: %q is outside of the root directory %q", pattern, root)
+			}
+			matches, err := filepath.Glob(abs)
+			if err != nil {
+				return nil, err
+			}
+			out := []string{}
+			for _, v := range matches {
+				//the matches linking outside of the root directory are dropped
+				if resolved, err := filepath.EvalSymlinks(v); err != nil || !insideDir(root, resolved) {
+					continue
+				}
+				if filepath.IsAbs(pattern) {
+					out = append(out, v)
+				} else if rel, err := filepath.Rel(dir, v); err == nil {
+					out = append(out, filepath.ToSlash(rel))
+				}
+			}
+			sort.Strings(out)
+			return out, nil
+		},
+	}
+}
+
+//fileDirs returns the absolute directory of the relative paths and the root directory without symlinks,
+//both are the working directory by default
+func (p *Pipes) fileDirs() (string, string, error) {
+	dir, root := p.fileDir, p.fileRoot
+	if dir == "" {
+		dir = "."
+	}
+	if root == "" {
+		root = dir
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return "", "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", "", err
+	}
+	return dir, root, nil
+}
+
+//resolve returns the file of a path relative to the template directory, following symlinks,
+//when it is inside of the root directory
+func (p *Pipes) resolve(pipe, name string) (string, error) {
+	dir, root, err := p.fileDirs()
+	if err != nil {
+		return "", err
+	}
+	file := name
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	if !insideDir(root, filepath.Clean(file)) {
+		return "", fmt.Errorf("%s: %q is outside of the root directory %q", pipe, name, root)
+	}
+	resolved, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return "", err
+	}
+	if !insideDir(root, resolved) {
+		return "", fmt.Errorf("%s: %q links outside of the root directory %q", pipe, name, root)
+	}
+	if fi, err := os.Stat(resolved); err == nil && fi.IsDir() {
+		return "", fmt.Errorf("%s: %q is a directory", pipe, name)
+	}
+	return resolved, nil
+}
+
+func insideDir(dir, file string) bool {
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+var filePipesInfo = map[string]PipeInfo{
+	"readFile": {
+		Description: "Reads a file relative to the template directory within the root directory.",
+		Examples:    []string{` + "`" + `{{readFile "certs/ca.pem" | indent 6}}` + "`" + `},
+	},
+	"readLines": {
+		Description: "Reads the lines of a file relative to the template directory within the root directory.",
+		Examples:    []string{` + "`" + `{{range readLines "hosts.txt"}}...{{end}}` + "`" + `},
+	},
+	"fileGlob": {
+		Description: "Returns the sorted files matching a pattern relative to the template directory within the root directory.",
+		Examples:    []string{` + "`" + `{{range fileGlob "scripts/*.sh"}}{{readFile .}}{{end}}` + "`" + `},
+	},
+}
+`
+	PipesHtmlGo = `package runtime
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+//HTMLTemplate converts the parsed templates of t into a html/template set that escapes the values
+//according to their HTML, attribute, JS or URL context, the options are set as with template.Option.
+//From then on include and tpl render with html/template
+func (p *Pipes) HTMLTemplate(t *template.Template, options ...string) (*htmltemplate.Template, error) {
+	h, err := p.htmlTemplate(t, options)
+	if err != nil {
+		return nil, err
+	}
+	p.html, p.htmlOptions = h, options
+	return h, nil
+}
+
+func (p *Pipes) htmlTemplate(t *template.Template, options []string) (*htmltemplate.Template, error) {
+	h := htmltemplate.New(t.Name()).Funcs(htmltemplate.FuncMap(p.Map)).Option(options...)
+	for _, v := range t.Templates() {
+		if v.Tree == nil {
+			continue
+		}
+		if _, err := h.AddParseTree(v.Name(), v.Tree.Copy()); err != nil {
+			return nil, err
+		}
+	}
+	//AddParseTree adds the root as a new template of the set
+	if root := h.Lookup(t.Name()); root != nil {
+		return root, nil
+	}
+	return h, nil
+}
+
+//keepHTML returns s as HTML when v is HTML, so that e.g. the output of include stays escaped once
+//when it is indented
+func keepHTML(v interface{}, s string) interface{} {
+	if _, ok := v.(htmltemplate.HTML); ok {
+		return htmltemplate.HTML(s)
+	}
+	return s
+}
+
+//htmlPipes are helper functions for marking trusted values that html/template does not escape,
+//they have no effect with text/template
+func htmlPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. safeHTML "<b>bold</b>"
+		"safeHTML": func(s string) (htmltemplate.HTML, error) {
+			return htmltemplate.HTML(s), nil
+		},
+		//e.g. <div {{.Attrs | safeAttr}}>
+		"safeAttr": func(s string) (htmltemplate.HTMLAttr, error) {
+			return htmltemplate.HTMLAttr(s), nil
+		},
+		"safeJS": func(s string) (htmltemplate.JS, error) {
+			return htmltemplate.JS(s), nil
+		},
+		"safeURL": func(s string) (htmltemplate.URL, error) {
+			return htmltemplate.URL(s), nil
+		},
+		"safeCSS": func(s string) (htmltemplate.CSS, error) {
+			return htmltemplate.CSS(s), nil
+		},
+	}
+}
+
+var htmlPipesInfo = map[string]PipeInfo{
+	"safeHTML": {
+		Description: "Marks a trusted string as HTML that --engine html does not escape.",
+		Examples:    []string{` + "`" + `{{.Description | safeHTML}}` + "`" + `},
+	},
+	"safeAttr": {
+		Description: "Marks a trusted string as HTML attributes that --engine html does not escape.",
+		Examples:    []string{` + "`" + `<div {{.Attrs | safeAttr}}>` + "`" + `},
+	},
+	"safeJS": {
+		Description: "Marks a trusted string as a JavaScript expression that --engine html does not escape.",
+		Examples:    []string{` + "`" + `<script>var config = {{.Config | safeJS}};</script>` + "`" + `},
+	},
+	"safeURL": {
+		Description: "Marks a trusted string as a URL that --engine html does not filter or escape.",
+		Examples:    []string{` + "`" + `<a href="{{.Link | safeURL}}">` + "`" + `},
+	},
+	"safeCSS": {
+		Description: "Marks a trusted string as CSS that --engine html does not escape.",
+		Examples:    []string{` + "`" + `<p style="{{.Style | safeCSS}}">` + "`" + `},
+	},
+}
+`
+	PipesIncludeGo = `package runtime
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+)
+
+//maxIncludeDepth limits the nested include and tpl calls so that a recursive template fails instead of looping
+const maxIncludeDepth = 32
+
+//SetTemplate sets the template whose named templates are rendered by include and tpl
+func (p *Pipes) SetTemplate(t *template.Template) {
+	p.tmpl = t
+}
+
+//includePipes are helper functions for rendering templates into a string that can be piped,
+//with html/template the result is HTML that is not escaped again
+func (p *Pipes) includePipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. include "job" . | indent 2
+		"include": func(name string, data interface{}) (interface{}, error) {
+			if p.tmpl == nil || p.tmpl.Lookup(name) == nil {
+				return "", fmt.Errorf("include: template %q is not defined", name)
+			}
+			if p.html != nil {
+				return p.renderHTML("include", name, p.html, data)
+			}
+			return p.render("include", name, p.tmpl, data)
+		},
+		//e.g. tpl .Config.Message .  => renders the field as a template with the named templates
+		"tpl": func(text string, data interface{}) (interface{}, error) {
+			t := template.New("tpl").Funcs(p.Map)
+			if p.tmpl != nil {
+				var err error
+				if t, err = p.tmpl.Clone(); err != nil {
+					return "", err
+				}
+				t = t.New("tpl")
+			}
+			if _, err := t.Parse(text); err != nil {
+				return "", err
+			}
+			if p.html != nil {
+				h, err := p.htmlTemplate(t, p.htmlOptions)
+				if err != nil {
+					return "", err
+				}
+				return p.renderHTML("tpl", "tpl", h, data)
+			}
+			return p.render("tpl", "tpl", t, data)
+		},
+	}
+}
+
+//executor is a text/template or a html/template set
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+func (p *Pipes) render(pipe, name string, t executor, data interface{}) (string, error) {
+	if p.depth >= maxIncludeDepth {
+		return "", fmt.Errorf("%s: template %q exceeds the maximum depth of %d", pipe, name, maxIncludeDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (p *Pipes) renderHTML(pipe, name string, t executor, data interface{}) (htmltemplate.HTML, error) {
+	s, err := p.render(pipe, name, t, data)
+	return htmltemplate.HTML(s), err
+}
+
+var includePipesInfo = map[string]PipeInfo{
+	"include": {
+		Description: "Renders a named template into a string that can be piped.",
+		Examples:    []string{` + "`" + `{{include "job" . | indent 2}}` + "`" + `},
+	},
+	"tpl": {
+		Description: "Renders a string as a template, it can use the named templates.",
+		Examples:    []string{` + "`" + `{{tpl .Config.Message .}}` + "`" + `},
+	},
+}
+`
+	PipesMathGo = `package runtime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var (
+	errOverflow     = errors.New("integer overflow")
+	errDivideByZero = errors.New("integer divide by zero")
+)
+
+//maxSeqLen guards against ranging over an accidental huge count
+const maxSeqLen = 1000000
+
+//mathPipes are helper functions for arithmetic; the piped value is the left operand
+//e.g. {{.Index | add 8080}} => .Index + 8080, {{.Count | sub 1}} => .Count - 1
+func mathPipes() template.FuncMap {
+	return template.FuncMap{
+		"add": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+					return 0, errOverflow
+				}
+				return x + y, nil
+			})
+		},
+		"sub": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if (y < 0 && x > math.MaxInt64+y) || (y > 0 && x < math.MinInt64+y) {
+					return 0, errOverflow
+				}
+				return x - y, nil
+			})
+		},
+		"mul": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if x == 0 || y == 0 {
+					return 0, nil
+				}
+				r := x * y
+				if r/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+					return 0, errOverflow
+				}
+				return r, nil
+			})
+		},
+		"div": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if y == 0 {
+					return 0, errDivideByZero
+				}
+				if x == math.MinInt64 && y == -1 {
+					return 0, errOverflow
+				}
+				return x / y, nil
+			})
+		},
+		"mod": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if y == 0 {
+					return 0, errDivideByZero
+				}
+				if y == -1 {
+					return 0, nil
+				}
+				return x % y, nil
+			})
+		},
+		//e.g. max 1 .A .B  => the largest value
+		"max": func(a interface{}, rest ...interface{}) (int64, error) {
+			return intFold(a, rest, func(x, y int64) bool { return y > x })
+		},
+		"min": func(a interface{}, rest ...interface{}) (int64, error) {
+			return intFold(a, rest, func(x, y int64) bool { return y < x })
+		},
+		"floor": func(v interface{}) (float64, error) {
+			f, err := toFloat64(v)
+			return math.Floor(f), err
+		},
+		"ceil": func(v interface{}) (float64, error) {
+			f, err := toFloat64(v)
+			return math.Ceil(f), err
+		},
+		"atoi": func(v interface{}) (int, error) {
+			return strconv.Atoi(toString(v))
+		},
+		//e.g. formatFloat 2 3.14159  => "3.14"
+		"formatFloat": func(prec int, v interface{}) (string, error) {
+			f, err := toFloat64(v)
+			if err != nil {
+				return "", err
+			}
+			return strconv.FormatFloat(f, 'f', prec, 64), nil
+		},
+		//e.g. humanizeBytes 1572864  => "1.5 MiB"
+		"humanizeBytes": func(v interface{}) (string, error) {
+			n, err := toInt64(v)
+			if err != nil {
+				return "", err
+			}
+			return humanizeBytes(n), nil
+		},
+		//e.g. seq 3  => [1 2 3], seq 2 4  => [2 3 4], seq 10 -5 1  => [10 5]
+		"seq": func(a ...int) ([]int, error) {
+			first, step, last := 1, 1, 0
+			switch len(a) {
+			case 1:
+				last = a[0]
+			case 2:
+				first, last = a[0], a[1]
+			case 3:
+				first, step, last = a[0], a[1], a[2]
+			default:
+				return nil, fmt.Errorf("seq: wrong number of args: got %d want 1 to 3", len(a))
+			}
+			return sequence(first, step, last)
+		},
+		//e.g. until 3  => [0 1 2]
+		"until": func(n int) ([]int, error) {
+			if n <= 0 {
+				return []int{}, nil
+			}
+			return sequence(0, 1, n-1)
+		},
+	}
+}
+
+func intOp(a, b interface{}, op func(x, y int64) (int64, error)) (int64, error) {
+	x, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toInt64(b)
+	if err != nil {
+		return 0, err
+	}
+	return op(x, y)
+}
+
+func intFold(a interface{}, rest []interface{}, replace func(x, y int64) bool) (int64, error) {
+	out, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range rest {
+		n, err := toInt64(v)
+		if err != nil {
+			return 0, err
+		}
+		if replace(out, n) {
+			out = n
+		}
+	}
+	return out, nil
+}
+
+func sequence(first, step, last int) ([]int, error) {
+	if step == 0 {
+		return nil, errors.New("seq: step cannot be zero")
+	}
+	out := []int{}
+	for i := first; (step > 0 && i <= last) || (step < 0 && i >= last); i += step {
+		if len(out) == maxSeqLen {
+			return nil, fmt.Errorf("seq: more than %d values", maxSeqLen)
+		}
+		out = append(out, i)
+	}
+	return out, nil
+}
+
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	f := float64(n)
+	exp := 0
+	for math.Abs(f) >= unit && exp < 6 {
+		f /= unit
+		exp++
+	}
+	return fmt.Sprintf("%s %ciB", strings.TrimSuffix(strconv.FormatFloat(f, 'f', 1, 64), ".0"), "KMGTPE"[exp-1])
+}
+
+//toInt64 accepts any integer kind, an integral float or a numeric string
+func toInt64(v interface{}) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, errOverflow
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("cannot convert %v to int", f)
+		}
+		if f >= math.MaxInt64 || f < math.MinInt64 {
+			return 0, errOverflow
+		}
+		return int64(f), nil
+	case reflect.String:
+		return strconv.ParseInt(rv.String(), 10, 64)
+	}
+	return 0, fmt.Errorf("cannot convert %T to int", v)
+}
+
+//toFloat64 accepts any integer or float kind or a numeric string
+func toFloat64(v interface{}) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(rv.String(), 64)
+	}
+	return 0, fmt.Errorf("cannot convert %T to float", v)
+}
+
+var mathPipesInfo = map[string]PipeInfo{
+	"add": {
+		Description: "Adds to the piped integer.",
+		Examples:    []string{` + "`" + `{{.Index | add 8080}}` + "`" + `},
+	},
+	"sub": {
+		Description: "Subtracts from the piped integer.",
+		Examples:    []string{` + "`" + `{{.Count | sub 1}}` + "`" + `},
+	},
+	"mul": {
+		Description: "Multiplies the piped integer.",
+		Examples:    []string{` + "`" + `{{.Count | mul 2}}` + "`" + `},
+	},
+	"div": {
+		Description: "Divides the piped integer.",
+		Examples:    []string{` + "`" + `{{.Count | div 2}}` + "`" + `},
+	},
+	"mod": {
+		Description: "Returns the remainder of dividing the piped integer.",
+		Examples:    []string{` + "`" + `{{.Index | mod 2}}` + "`" + `},
+	},
+	"max": {
+		Description: "Returns the largest integer.",
+		Examples:    []string{` + "`" + `{{max 1 .A .B}}` + "`" + `},
+	},
+	"min": {
+		Description: "Returns the smallest integer.",
+		Examples:    []string{` + "`" + `{{min 1 .A .B}}` + "`" + `},
+	},
+	"floor": {
+		Description: "Returns the greatest integer value less than or equal to the number.",
+		Examples:    []string{` + "`" + `{{.Ratio | floor}}` + "`" + `},
+	},
+	"ceil": {
+		Description: "Returns the least integer value greater than or equal to the number.",
+		Examples:    []string{` + "`" + `{{.Ratio | ceil}}` + "`" + `},
+	},
+	"atoi": {
+		Description: "Converts a string to an integer.",
+		Examples:    []string{` + "`" + `{{.Port | atoi}}` + "`" + `},
+	},
+	"formatFloat": {
+		Description: "Formats a number with the precision.",
+		Examples:    []string{` + "`" + `{{.Ratio | formatFloat 2}}` + "`" + `},
+	},
+	"humanizeBytes": {
+		Description: "Formats a number of bytes with binary units.",
+		Examples:    []string{` + "`" + `{{.Size | humanizeBytes}}` + "`" + `},
+	},
+	"seq": {
+		Description: "Returns the integers of seq LAST, seq FIRST LAST or seq FIRST STEP LAST.",
+		Examples:    []string{` + "`" + `{{range seq 1 3}}...{{end}}` + "`" + `},
+	},
+	"until": {
+		Description: "Returns the integers from 0 to the count - 1.",
+		Examples:    []string{` + "`" + `{{range .Count | until}}...{{end}}` + "`" + `},
+	},
+}
+`
+	PipesMissingGo = `package runtime
+
+import (
+	"errors"
+	"reflect"
+	"text/template"
+)
+
+//missingPipes are helper functions for dealing with missing or empty data
+func missingPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. default "guest" .Name  => "guest" when .Name is empty
+		"default": func(d, v interface{}) (interface{}, error) {
+			if isEmpty(v) {
+				return d, nil
+			}
+			return v, nil
+		},
+		//e.g. coalesce .Name .Nick "guest"  => the first non-empty value
+		"coalesce": func(a ...interface{}) (interface{}, error) {
+			for _, v := range a {
+				if !isEmpty(v) {
+					return v, nil
+				}
+			}
+			return nil, nil
+		},
+		"empty": func(v interface{}) (bool, error) {
+			return isEmpty(v), nil
+		},
+		//e.g. ternary "on" "off" .Enabled
+		"ternary": func(t, f interface{}, cond bool) (interface{}, error) {
+			if cond {
+				return t, nil
+			}
+			return f, nil
+		},
+		//e.g. required "password is missing" .Private.Password
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if isEmpty(v) {
+				return nil, errors.New(msg)
+			}
+			return v, nil
+		},
+		"fail": func(msg string) (string, error) {
+			return "", errors.New(msg)
+		},
+	}
+}
+
+//isEmpty reports whether v is nil or the zero value of its type
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String, reflect.Chan:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return rv.Complex() == 0
+	case reflect.Interface, reflect.Ptr, reflect.Func:
+		return rv.IsNil()
+	case reflect.Struct:
+		return reflect.DeepEqual(v, reflect.Zero(rv.Type()).Interface())
+	}
+	return false
+}
+
+var missingPipesInfo = map[string]PipeInfo{
+	"default": {
+		Description: "Returns the default when the value is empty.",
+		Examples:    []string{` + "`" + `{{.Name | default "guest"}}` + "`" + `},
+	},
+	"coalesce": {
+		Description: "Returns the first non-empty value.",
+		Examples:    []string{` + "`" + `{{coalesce .Name .Nick "guest"}}` + "`" + `},
+	},
+	"empty": {
+		Description: "Reports whether the value is nil or the zero value of its type.",
+		Examples:    []string{` + "`" + `{{if .List | empty}}...{{end}}` + "`" + `},
+	},
+	"ternary": {
+		Description: "Returns the first value when the condition is true, otherwise the second.",
+		Examples:    []string{` + "`" + `{{.Enabled | ternary "on" "off"}}` + "`" + `},
+	},
+	"required": {
+		Description: "Aborts the render with the message when the value is empty.",
+		Examples:    []string{` + "`" + `{{.Private.Password | required "password is missing"}}` + "`" + `},
+	},
+	"fail": {
+		Description: "Aborts the render with the message.",
+		Examples:    []string{` + "`" + `{{fail "unsupported platform"}}` + "`" + `},
+	},
+}
+`
+	PipesNetworkGo = `package runtime
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"strings"
+	"text/template"
+)
+
+//networkPipes are helper functions for computing addresses from an IP or a CIDR
+func networkPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. cidrHost 5 "10.0.0.0/24"  => "10.0.0.5", a negative number counts from the end of the range
+		"cidrHost": func(hostnum int, v interface{}) (string, error) {
+			cidr := toString(v)
+			prefix, err := parseCIDR(cidr)
+			if err != nil {
+				return "", err
+			}
+			hostBits := uint(prefix.Addr().BitLen() - prefix.Bits())
+			size := new(big.Int).Lsh(big.NewInt(1), hostBits)
+			num := big.NewInt(int64(hostnum))
+			if hostnum < 0 {
+				num.Add(num, size)
+			}
+			if num.Sign() < 0 || num.Cmp(size) >= 0 {
+				return "", fmt.Errorf("cidrHost: host number %d is out of range for %s", hostnum, cidr)
+			}
+			addr, err := addToAddr(prefix.Addr(), num)
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+		//e.g. cidrSubnet 8 2 "10.0.0.0/16"  => "10.0.2.0/24"
+		"cidrSubnet": func(newbits, netnum int, v interface{}) (string, error) {
+			cidr := toString(v)
+			prefix, err := parseCIDR(cidr)
+			if err != nil {
+				return "", err
+			}
+			bits := prefix.Bits() + newbits
+			if newbits < 0 || bits > prefix.Addr().BitLen() {
+				return "", fmt.Errorf("cidrSubnet: cannot extend %s by %d bits", cidr, newbits)
+			}
+			if netnum < 0 || big.NewInt(int64(netnum)).Cmp(new(big.Int).Lsh(big.NewInt(1), uint(newbits))) >= 0 {
+				return "", fmt.Errorf("cidrSubnet: network number %d is out of range for %d new bits", netnum, newbits)
+			}
+			offset := new(big.Int).Lsh(big.NewInt(int64(netnum)), uint(prefix.Addr().BitLen()-bits))
+			addr, err := addToAddr(prefix.Addr(), offset)
+			if err != nil {
+				return "", err
+			}
+			return netip.PrefixFrom(addr, bits).String(), nil
+		},
+		//e.g. cidrNetmask "10.0.0.0/20"  => "255.255.240.0"
+		"cidrNetmask": func(v interface{}) (string, error) {
+			prefix, err := parseCIDR(toString(v))
+			if err != nil {
+				return "", err
+			}
+			return net.IP(net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen())).String(), nil
+		},
+		//e.g. ipAdd 10 "10.0.0.1"  => "10.0.0.11"
+		"ipAdd": func(n int, v interface{}) (string, error) {
+			addr, err := parseIP(toString(v))
+			if err != nil {
+				return "", err
+			}
+			addr, err = addToAddr(addr, big.NewInt(int64(n)))
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+		//e.g. ipInRange "10.0.0.10" "10.0.0.20" .IP  => true when start <= IP <= end
+		"ipInRange": func(start, end string, v interface{}) (bool, error) {
+			addrs := make([]netip.Addr, 3)
+			for k, v := range []string{start, end, toString(v)} {
+				addr, err := parseIP(v)
+				if err != nil {
+					return false, err
+				}
+				addrs[k] = addr
+			}
+			if addrs[0].BitLen() != addrs[2].BitLen() || addrs[1].BitLen() != addrs[2].BitLen() {
+				return false, nil
+			}
+			return addrs[0].Compare(addrs[2]) <= 0 && addrs[2].Compare(addrs[1]) <= 0, nil
+		},
+		//e.g. cidrContains "10.0.0.0/16" .IP, the value can also be a CIDR e.g. "10.0.1.0/24"
+		"cidrContains": func(cidr string, v interface{}) (bool, error) {
+			prefix, err := parseCIDR(cidr)
+			if err != nil {
+				return false, err
+			}
+			s := toString(v)
+			if strings.Contains(s, "/") {
+				other, err := parseCIDR(s)
+				if err != nil {
+					return false, err
+				}
+				return other.Bits() >= prefix.Bits() && prefix.Contains(other.Addr()), nil
+			}
+			addr, err := parseIP(s)
+			if err != nil {
+				return false, err
+			}
+			return prefix.Contains(addr), nil
+		},
+	}
+}
+
+//parseCIDR returns the masked prefix of a CIDR e.g. "10.0.0.1/24" => 10.0.0.0/24
+func parseCIDR(cidr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", cidr)
+	}
+	return prefix.Masked(), nil
+}
+
+func parseIP(ip string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid IP address %q", ip)
+	}
+	return addr.Unmap(), nil
+}
+
+//addToAddr adds n to an address without leaving its address family
+func addToAddr(addr netip.Addr, n *big.Int) (netip.Addr, error) {
+	sum := new(big.Int).SetBytes(addr.AsSlice())
+	sum.Add(sum, n)
+	size := addr.BitLen() / 8
+	if sum.Sign() < 0 || len(sum.Bytes()) > size {
+		return netip.Addr{}, fmt.Errorf("%s %+d is out of the address range", addr, n)
+	}
+	buf := make([]byte, size)
+	sum.FillBytes(buf)
+	out, _ := netip.AddrFromSlice(buf)
+	return out, nil
+}
+
+var networkPipesInfo = map[string]PipeInfo{
+	"cidrHost": {
+		Description: "Returns the address of a host number within a CIDR, a negative number counts from the end.",
+		Examples:    []string{` + "`" + `{{"10.0.0.0/24" | cidrHost 5}}` + "`" + `},
+	},
+	"cidrSubnet": {
+		Description: "Returns the subnet of a CIDR extended by the new bits with the network number.",
+		Examples:    []string{` + "`" + `{{"10.1.0.0/16" | cidrSubnet 8 2}}` + "`" + `},
+	},
+	"cidrNetmask": {
+		Description: "Returns the netmask of a CIDR.",
+		Examples:    []string{` + "`" + `{{"10.0.0.0/20" | cidrNetmask}}` + "`" + `},
+	},
+	"ipAdd": {
+		Description: "Adds a number to an IP address.",
+		Examples:    []string{` + "`" + `{{"10.0.0.1" | ipAdd 10}}` + "`" + `},
+	},
+	"ipInRange": {
+		Description: "Reports whether an IP address is between the start and end addresses.",
+		Examples:    []string{` + "`" + `{{.IP | ipInRange "10.0.0.10" "10.0.0.20"}}` + "`" + `},
+	},
+	"cidrContains": {
+		Description: "Reports whether a CIDR contains an IP address or another CIDR.",
+		Examples:    []string{` + "`" + `{{.IP | cidrContains "10.0.0.0/16"}}` + "`" + `},
+	},
+}
+`
+	PipesPathGo = `package runtime
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"text/template"
+)
+
+//ParsedURL is the result of urlParse e.g. {{(urlParse .Repo).Host}}
+type ParsedURL struct {
+	Scheme   string
+	User     string
+	Host     string
+	Port     string
+	Path     string
+	Query    url.Values
+	Fragment string
+
+	url *url.URL
+}
+
+func (u ParsedURL) String() string {
+	return u.url.String()
+}
+
+//pathPipes are helper functions for splitting and building slash separated paths and URLs
+func pathPipes() template.FuncMap {
+	return template.FuncMap{
+		"base": func(v interface{}) (string, error) {
+			return path.Base(toString(v)), nil
+		},
+		"dir": func(v interface{}) (string, error) {
+			return path.Dir(toString(v)), nil
+		},
+		"ext": func(v interface{}) (string, error) {
+			return path.Ext(toString(v)), nil
+		},
+		"clean": func(v interface{}) (string, error) {
+			return path.Clean(toString(v)), nil
+		},
+		//e.g. pathJoin "/etc" "app" .File  => "/etc/app/config.yml"
+		"pathJoin": func(elem ...interface{}) (string, error) {
+			return path.Join(toStrings(elem)...), nil
+		},
+		"urlParse": func(v interface{}) (ParsedURL, error) {
+			u, err := parseURL(toString(v))
+			if err != nil {
+				return ParsedURL{}, err
+			}
+			return ParsedURL{
+				Scheme:   u.Scheme,
+				User:     u.User.Username(),
+				Host:     u.Hostname(),
+				Port:     u.Port(),
+				Path:     u.Path,
+				Query:    u.Query(),
+				Fragment: u.Fragment,
+				url:      u,
+			}, nil
+		},
+		//e.g. urlJoin "https://github.com/jane" "repo1.git"  => "https://github.com/jane/repo1.git"
+		"urlJoin": func(base string, elem ...interface{}) (string, error) {
+			u, err := parseURL(base)
+			if err != nil {
+				return "", err
+			}
+			return u.JoinPath(toStrings(elem)...).String(), nil
+		},
+		//e.g. urlSetQuery "ref" "develop" .Repo  => "https://github.com/jane/repo1?ref=develop"
+		"urlSetQuery": func(key string, value, v interface{}) (string, error) {
+			u, err := parseURL(toString(v))
+			if err != nil {
+				return "", err
+			}
+			q := u.Query()
+			q.Set(key, toString(value))
+			u.RawQuery = q.Encode()
+			return u.String(), nil
+		},
+	}
+}
+
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %s", s, err.Error())
+	}
+	return u, nil
+}
+
+var pathPipesInfo = map[string]PipeInfo{
+	"base": {
+		Description: "Returns the last element of a path.",
+		Examples:    []string{` + "`" + `{{.File | base}}` + "`" + `},
+	},
+	"dir": {
+		Description: "Returns all but the last element of a path.",
+		Examples:    []string{` + "`" + `{{.File | dir}}` + "`" + `},
+	},
+	"ext": {
+		Description: "Returns the file name extension of a path.",
+		Examples:    []string{` + "`" + `{{.File | ext}}` + "`" + `},
+	},
+	"clean": {
+		Description: "Returns the shortest equivalent path.",
+		Examples:    []string{` + "`" + `{{.File | clean}}` + "`" + `},
+	},
+	"pathJoin": {
+		Description: "Joins the elements of a path.",
+		Examples:    []string{` + "`" + `{{.File | pathJoin "/etc" "app"}}` + "`" + `},
+	},
+	"urlParse": {
+		Description: "Parses a URL with Scheme, User, Host, Port, Path, Query and Fragment fields.",
+		Examples:    []string{` + "`" + `{{(.Repo | urlParse).Host}}` + "`" + `},
+	},
+	"urlJoin": {
+		Description: "Joins the elements to the path of a URL.",
+		Examples:    []string{` + "`" + `{{"repo1.git" | urlJoin "https://github.com/jane"}}` + "`" + `},
+	},
+	"urlSetQuery": {
+		Description: "Sets a query parameter of a URL.",
+		Examples:    []string{` + "`" + `{{.Repo | urlSetQuery "ref" .Branch}}` + "`" + `},
+	},
+}
+`
+	PipesRegexGo = `package runtime
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"text/template"
+)
+
+//regexCache holds the patterns compiled during a render
+type regexCache struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func (c *regexCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.Lock()
+	defer c.Unlock()
+	if re, ok := c.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err.Error())
+	}
+	if c.patterns == nil {
+		c.patterns = make(map[string]*regexp.Regexp)
+	}
+	c.patterns[pattern] = re
+	return re, nil
+}
+
+//regexPipes are helper functions for regular expressions, patterns are compiled once per Pipes
+func (p *Pipes) regexPipes() template.FuncMap {
+	return template.FuncMap{
+		"regexMatch": func(pattern string, v interface{}) (bool, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return false, err
+			}
+			return re.MatchString(toString(v)), nil
+		},
+		"regexFind": func(pattern string, v interface{}) (string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.FindString(toString(v)), nil
+		},
+		//e.g. regexFindAll "[0-9]+" -1 .Text  => all of the matches
+		"regexFindAll": func(pattern string, n int, v interface{}) ([]string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			out := re.FindAllString(toString(v), n)
+			if out == nil {
+				return []string{}, nil
+			}
+			return out, nil
+		},
+		//e.g. regexReplaceAll "(\\w+)@(\\w+)" "${2}/${1}" .Email  => capture groups are referenced with $1 or ${1}
+		"regexReplaceAll": func(pattern, repl string, v interface{}) (string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.ReplaceAllString(toString(v), repl), nil
+		},
+		//e.g. regexSplit "\\s*,\\s*" -1 .List
+		"regexSplit": func(pattern string, n int, v interface{}) ([]string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			return re.Split(toString(v), n), nil
+		},
+	}
+}
+
+var regexPipesInfo = map[string]PipeInfo{
+	"regexMatch": {
+		Description: "Reports whether the string matches the pattern.",
+		Examples:    []string{` + "`" + `{{if .Version | regexMatch "^v[0-9]+"}}...{{end}}` + "`" + `},
+	},
+	"regexFind": {
+		Description: "Returns the first match of the pattern.",
+		Examples:    []string{` + "`" + `{{.Text | regexFind "[0-9]+"}}` + "`" + `},
+	},
+	"regexFindAll": {
+		Description: "Returns at most n matches of the pattern, -1 for all of them.",
+		Examples:    []string{` + "`" + `{{.Text | regexFindAll "[0-9]+" -1}}` + "`" + `},
+	},
+	"regexReplaceAll": {
+		Description: "Replaces the matches of the pattern, capture groups are referenced with $1 or ${1}.",
+		Examples:    []string{` + "`" + `{{.Email | regexReplaceAll "(\\w+)@(\\w+)" "${2}/${1}"}}` + "`" + `},
+	},
+	"regexSplit": {
+		Description: "Splits the string into at most n parts around the matches of the pattern, -1 for all of them.",
+		Examples:    []string{` + "`" + `{{.List | regexSplit "\\s*,\\s*" -1}}` + "`" + `},
+	},
+}
+`
+	PipesSemverGo = `package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//SemVer is a parsed semantic version e.g. {{(semver "v1.2.3-rc.1").Minor}} => 2
+type SemVer struct {
+	Major, Minor, Patch int64
+	Prerelease          string
+	Metadata            string
+	Original            string
+}
+
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Metadata != "" {
+		s += "+" + v.Metadata
+	}
+	return s
+}
+
+//Compare returns -1, 0 or 1 following the semver precedence rules, metadata is ignored
+func (v SemVer) Compare(o SemVer) int {
+	for _, d := range []int64{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+//semverPipes are helper functions for parsing, comparing and sorting semantic versions
+func semverPipes() template.FuncMap {
+	return template.FuncMap{
+		"semver": func(v interface{}) (SemVer, error) {
+			return toSemVer(v)
+		},
+		//e.g. semverCompare ">=1.2, <2 || ^3.1" .Version  => true when the version satisfies the constraint
+		"semverCompare": func(constraint string, v interface{}) (bool, error) {
+			c, err := parseSemVerConstraint(constraint)
+			if err != nil {
+				return false, err
+			}
+			version, err := toSemVer(v)
+			if err != nil {
+				return false, err
+			}
+			return c.check(version), nil
+		},
+		//e.g. semverBump "minor" "1.2.3"  => 1.3.0
+		"semverBump": func(part string, v interface{}) (SemVer, error) {
+			version, err := toSemVer(v)
+			if err != nil {
+				return SemVer{}, err
+			}
+			out := SemVer{Major: version.Major, Minor: version.Minor, Patch: version.Patch}
+			//a prerelease of the bumped version is bumped to its release e.g. minor 1.3.0-rc.1 => 1.3.0
+			pre := version.Prerelease != ""
+			switch part {
+			case "major":
+				if !pre || out.Minor != 0 || out.Patch != 0 {
+					out.Major++
+				}
+				out.Minor, out.Patch = 0, 0
+			case "minor":
+				if !pre || out.Patch != 0 {
+					out.Minor++
+				}
+				out.Patch = 0
+			case "patch":
+				if !pre {
+					out.Patch++
+				}
+			default:
+				return SemVer{}, fmt.Errorf("semverBump: unknown part %q (major, minor or patch)", part)
+			}
+			out.Original = out.String()
+			return out, nil
+		},
+		//e.g. semverSort .Tags  => the tags from the lowest to the highest version
+		"semverSort": func(a interface{}) ([]string, error) {
+			rv := reflect.ValueOf(a)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return nil, fmt.Errorf("semverSort: cannot sort %T", a)
+			}
+			versions := make([]SemVer, rv.Len())
+			for i := range versions {
+				version, err := toSemVer(rv.Index(i).Interface())
+				if err != nil {
+					return nil, err
+				}
+				versions[i] = version
+			}
+			sort.SliceStable(versions, func(i, j int) bool {
+				return versions[i].Compare(versions[j]) < 0
+			})
+			out := make([]string, len(versions))
+			for k, v := range versions {
+				out[k] = v.Original
+			}
+			return out, nil
+		},
+	}
+}
+
+func toSemVer(v interface{}) (SemVer, error) {
+	switch s := v.(type) {
+	case SemVer:
+		return s, nil
+	case string:
+		return parseSemVer(s)
+	case fmt.Stringer:
+		return parseSemVer(s.String())
+	}
+	return SemVer{}, fmt.Errorf("cannot convert %T to semver", v)
+}
+
+//parseSemVer accepts an optional "v" prefix and missing minor or patch numbers e.g. "v1.2"
+func parseSemVer(s string) (SemVer, error) {
+	version, parts, err := parsePartialSemVer(s)
+	if err == nil && parts == 0 {
+		return SemVer{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+	return version, err
+}
+
+//parsePartialSemVer also returns how many of major, minor and patch are given before a wildcard ("x", "X" or "*")
+func parsePartialSemVer(s string) (SemVer, int, error) {
+	version := SemVer{Original: s}
+	invalid := fmt.Errorf("invalid semantic version %q", s)
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(rest, "+"); i >= 0 {
+		rest, version.Metadata = rest[:i], rest[i+1:]
+	}
+	if i := strings.Index(rest, "-"); i >= 0 {
+		rest, version.Prerelease = rest[:i], rest[i+1:]
+		if version.Prerelease == "" {
+			return SemVer{}, 0, invalid
+		}
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return SemVer{}, 0, invalid
+	}
+	numbers := []*int64{&version.Major, &version.Minor, &version.Patch}
+	for k, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			return version, k, nil
+		}
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return SemVer{}, 0, invalid
+		}
+		*numbers[k] = n
+	}
+	return version, len(parts), nil
+}
+
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	//a release has a higher precedence than its prereleases
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseInt(as[i], 10, 64)
+		bn, bErr := strconv.ParseInt(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(int64(len(as) - len(bs)))
+}
+
+func sign(n int64) int {
+	if n < 0 {
+		return -1
+	}
+	if n > 0 {
+		return 1
+	}
+	return 0
+}
+
+type semverComparator struct {
+	op      string
+	version SemVer
+}
+
+func (c semverComparator) check(v SemVer) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	}
+	return cmp <= 0
+}
+
+//semverConstraint is a list of "||" alternatives of "," separated comparators
+type semverConstraint [][]semverComparator
+
+//check follows the common convention that a prerelease only satisfies
+//a group of comparators that mentions a prerelease of the same version
+func (c semverConstraint) check(v SemVer) bool {
+	for _, group := range c {
+		ok := true
+		prerelease := v.Prerelease == ""
+		for _, comparator := range group {
+			if !comparator.check(v) {
+				ok = false
+				break
+			}
+			cv := comparator.version
+			if cv.Prerelease != "" && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+				prerelease = true
+			}
+		}
+		if ok && prerelease {
+			return true
+		}
+	}
+	return false
+}
+
+//parseSemVerConstraint supports =, !=, >, >=, <, <=, ~, ^ and wildcards e.g. ">=1.2, <2 || 3.x"
+func parseSemVerConstraint(s string) (semverConstraint, error) {
+	out := semverConstraint{}
+	for _, alternative := range strings.Split(s, "||") {
+		group := []semverComparator{}
+		for _, expr := range strings.Split(alternative, ",") {
+			comparators, err := parseSemVerComparator(strings.TrimSpace(expr))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", s, err.Error())
+			}
+			group = append(group, comparators...)
+		}
+		out = append(out, group)
+	}
+	return out, nil
+}
+
+func parseSemVerComparator(expr string) ([]semverComparator, error) {
+	op := ""
+	for _, o := range []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(expr, o) {
+			op = o
+			break
+		}
+	}
+	version, parts, err := parsePartialSemVer(strings.TrimSpace(strings.TrimPrefix(expr, op)))
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		return []semverComparator{{">=", SemVer{}}}, nil
+	}
+	wildcard := parts < 3
+	//upper is the first version beyond the range of the unspecified parts
+	upper := func(level int) SemVer {
+		switch level {
+		case 0:
+			return SemVer{Major: version.Major + 1}
+		case 1:
+			return SemVer{Major: version.Major, Minor: version.Minor + 1}
+		}
+		return SemVer{Major: version.Major, Minor: version.Minor, Patch: version.Patch + 1}
+	}
+	switch op {
+	case "", "=", "==":
+		if !wildcard {
+			return []semverComparator{{"=", version}}, nil
+		}
+		return []semverComparator{{">=", version}, {"<", upper(parts - 1)}}, nil
+	case "~":
+		if parts > 2 {
+			parts = 2
+		}
+		return []semverComparator{{">=", version}, {"<", upper(parts - 1)}}, nil
+	case "^":
+		level := 0
+		if version.Major == 0 && parts > 1 {
+			level = 1
+			if version.Minor == 0 && parts > 2 {
+				level = 2
+			}
+		}
+		return []semverComparator{{">=", version}, {"<", upper(level)}}, nil
+	case ">":
+		if wildcard {
+			return []semverComparator{{">=", upper(parts - 1)}}, nil
+		}
+	case "<=":
+		if wildcard {
+			return []semverComparator{{"<", upper(parts - 1)}}, nil
+		}
+	}
+	return []semverComparator{{op, version}}, nil
+}
+
+var semverPipesInfo = map[string]PipeInfo{
+	"semver": {
+		Description: "Parses a semantic version with Major, Minor, Patch, Prerelease and Metadata fields.",
+		Examples:    []string{` + "`" + `{{(.Version | semver).Minor}}` + "`" + `},
+	},
+	"semverCompare": {
+		Description: "Reports whether a semantic version satisfies the constraint.",
+		Examples:    []string{` + "`" + `{{if .Version | semverCompare ">=1.2, <2 || ^3.1"}}...{{end}}` + "`" + `},
+	},
+	"semverBump": {
+		Description: "Bumps the major, minor or patch part of a semantic version, a prerelease of that release is bumped to the release.",
+		Examples:    []string{` + "`" + `{{.Version | semverBump "minor"}}` + "`" + `},
+	},
+	"semverSort": {
+		Description: "Sorts a list of semantic versions from the lowest to the highest.",
+		Examples:    []string{` + "`" + `{{.Tags | semverSort}}` + "`" + `},
+	},
+}
+`
+	PipesStringsGo = `package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+//stringPipes are helper functions for trimming, casing, quoting and formatting strings,
+//the lengths and positions count characters rather than bytes
+func stringPipes() template.FuncMap {
+	return template.FuncMap{
+		"trim": func(v interface{}) (interface{}, error) {
+			return keepHTML(v, strings.TrimSpace(toString(v))), nil
+		},
+		"trimPrefix": func(prefix string, v interface{}) (string, error) {
+			return strings.TrimPrefix(toString(v), prefix), nil
+		},
+		"trimSuffix": func(suffix string, v interface{}) (string, error) {
+			return strings.TrimSuffix(toString(v), suffix), nil
+		},
+		//e.g. trimAll "/" "/jane/repo1/"  => "jane/repo1"
+		"trimAll": func(cutset string, v interface{}) (string, error) {
+			return strings.Trim(toString(v), cutset), nil
+		},
+		//e.g. title "hello wide world"  => "Hello Wide World"
+		"title": func(v interface{}) (string, error) {
+			r := []rune(toString(v))
+			for k, v := range r {
+				if k == 0 || unicode.IsSpace(r[k-1]) {
+					r[k] = unicode.ToTitle(v)
+				}
+			}
+			return string(r), nil
+		},
+		//e.g. camelCase "my-repo_name"  => "myRepoName"
+		"camelCase": func(v interface{}) (string, error) {
+			words := splitWords(toString(v))
+			for k, v := range words {
+				if k == 0 {
+					words[k] = strings.ToLower(v)
+					continue
+				}
+				r, size := utf8.DecodeRuneInString(v)
+				words[k] = string(unicode.ToUpper(r)) + strings.ToLower(v[size:])
+			}
+			return strings.Join(words, ""), nil
+		},
+		//e.g. snakeCase "HTTPServerName"  => "http_server_name"
+		"snakeCase": func(v interface{}) (string, error) {
+			return strings.ToLower(strings.Join(splitWords(toString(v)), "_")), nil
+		},
+		"kebabCase": func(v interface{}) (string, error) {
+			return strings.ToLower(strings.Join(splitWords(toString(v)), "-")), nil
+		},
+		//e.g. quote .Name  => "\"jane\"" with go escaping
+		"quote": func(v interface{}) (string, error) {
+			return strconv.Quote(toString(v)), nil
+		},
+		//e.g. squote "it's"  => "'it''s'", a single quote is escaped as in YAML and SQL
+		"squote": func(v interface{}) (string, error) {
+			return "'" + strings.Replace(toString(v), "'", "''", -1) + "'", nil
+		},
+		"repeat": func(n int, v interface{}) (string, error) {
+			if n < 0 {
+				return "", fmt.Errorf("repeat: negative count %d", n)
+			}
+			return strings.Repeat(toString(v), n), nil
+		},
+		//e.g. substr 0 4 "goflat"  => "gofl", a negative end is the end of the string
+		"substr": func(start, end int, v interface{}) (string, error) {
+			s := toString(v)
+			r := []rune(s)
+			if end < 0 || end > len(r) {
+				end = len(r)
+			}
+			if start < 0 || start > end {
+				return "", fmt.Errorf("substr: invalid range [%d:%d] of %q", start, end, s)
+			}
+			return string(r[start:end]), nil
+		},
+		//e.g. truncate 4 "goflat"  => "gofl"
+		"truncate": func(n int, v interface{}) (string, error) {
+			s := toString(v)
+			if n < 0 {
+				return "", fmt.Errorf("truncate: negative length %d", n)
+			}
+			r := []rune(s)
+			if n < len(r) {
+				return string(r[:n]), nil
+			}
+			return s, nil
+		},
+		//e.g. wrap 80 .Description, the words longer than the width are not broken
+		"wrap": func(width int, v interface{}) (string, error) {
+			if width < 1 {
+				return "", fmt.Errorf("wrap: invalid width %d", width)
+			}
+			return wrapWords(width, toString(v)), nil
+		},
+		"contains": func(substr string, v interface{}) (bool, error) {
+			return strings.Contains(toString(v), substr), nil
+		},
+		"hasPrefix": func(prefix string, v interface{}) (bool, error) {
+			return strings.HasPrefix(toString(v), prefix), nil
+		},
+		"hasSuffix": func(suffix string, v interface{}) (bool, error) {
+			return strings.HasSuffix(toString(v), suffix), nil
+		},
+		//e.g. indent 4 .Private.Key  => every line is indented by 4 spaces
+		"indent": func(n int, v interface{}) (interface{}, error) {
+			if n < 0 {
+				return "", fmt.Errorf("indent: negative width %d", n)
+			}
+			pad := strings.Repeat(" ", n)
+			return keepHTML(v, pad+strings.Replace(toString(v), "\n", "\n"+pad, -1)), nil
+		},
+		//e.g. padLeft 5 "42"  => "   42" or padLeft 5 "0" "42"  => "00042"
+		"padLeft": func(width int, a ...interface{}) (string, error) {
+			pad, s, err := padArgs("padLeft", a)
+			if err != nil {
+				return "", err
+			}
+			return padding(width, pad, s) + s, nil
+		},
+		"padRight": func(width int, a ...interface{}) (string, error) {
+			pad, s, err := padArgs("padRight", a)
+			if err != nil {
+				return "", err
+			}
+			return s + padding(width, pad, s), nil
+		},
+	}
+}
+
+//splitWords splits a string into words on the characters other than letters and digits
+//and on the case changes e.g. "HTTPServer_name" => ["HTTP", "Server", "name"]
+func splitWords(s string) []string {
+	words := []string{}
+	r := []rune(s)
+	start := -1
+	for k, v := range r {
+		if !unicode.IsLetter(v) && !unicode.IsDigit(v) {
+			if start >= 0 {
+				words = append(words, string(r[start:k]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = k
+			continue
+		}
+		prev := r[k-1]
+		lowerToUpper := unicode.IsUpper(v) && !unicode.IsUpper(prev)
+		acronymEnd := unicode.IsUpper(v) && unicode.IsUpper(prev) && k+1 < len(r) && unicode.IsLower(r[k+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(r[start:k]))
+			start = k
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(r[start:]))
+	}
+	return words
+}
+
+func wrapWords(width int, s string) string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line, size := "", 0
+		for _, word := range strings.Fields(paragraph) {
+			n := utf8.RuneCountInString(word)
+			if size > 0 && size+1+n > width {
+				lines = append(lines, line)
+				line, size = "", 0
+			}
+			if size > 0 {
+				line += " "
+				size++
+			}
+			line += word
+			size += n
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+//padArgs splits the args of padLeft and padRight into the optional pad, a space by default, and the piped string
+func padArgs(name string, a []interface{}) (string, string, error) {
+	switch len(a) {
+	case 1:
+		return " ", toString(a[0]), nil
+	case 2:
+		pad := toString(a[0])
+		if pad == "" {
+			return "", "", errors.New(name + ": empty pad")
+		}
+		return pad, toString(a[1]), nil
+	}
+	return "", "", fmt.Errorf("%s: wrong number of args: got %d want 2 or 3", name, len(a)+1)
+}
+
+func padding(width int, pad, s string) string {
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(strings.Repeat(pad, n))
+	return string(r[:n])
+}
+
+var stringPipesInfo = map[string]PipeInfo{
+	"trim": {
+		Description: "Removes the leading and trailing white space.",
+		Examples:    []string{` + "`" + `{{.Name | trim}}` + "`" + `},
+	},
+	"trimPrefix": {
+		Description: "Removes a leading prefix.",
+		Examples:    []string{` + "`" + `{{.Branch | trimPrefix "refs/heads/"}}` + "`" + `},
+	},
+	"trimSuffix": {
+		Description: "Removes a trailing suffix.",
+		Examples:    []string{` + "`" + `{{.Repo | trimSuffix ".git"}}` + "`" + `},
+	},
+	"trimAll": {
+		Description: "Removes the leading and trailing characters of a cutset.",
+		Examples:    []string{` + "`" + `{{.Path | trimAll "/"}}` + "`" + `},
+	},
+	"title": {
+		Description: "Capitalizes the first letter of every word.",
+		Examples:    []string{` + "`" + `{{.Name | title}}` + "`" + `},
+	},
+	"camelCase": {
+		Description: "Converts a string to camelCase.",
+		Examples:    []string{` + "`" + `{{.Name | camelCase}}` + "`" + `},
+	},
+	"snakeCase": {
+		Description: "Converts a string to snake_case.",
+		Examples:    []string{` + "`" + `{{.Name | snakeCase}}` + "`" + `},
+	},
+	"kebabCase": {
+		Description: "Converts a string to kebab-case.",
+		Examples:    []string{` + "`" + `{{.Name | kebabCase}}` + "`" + `},
+	},
+	"quote": {
+		Description: "Wraps a value in double quotes with Go escaping.",
+		Examples:    []string{` + "`" + `{{.Private.Password | quote}}` + "`" + `},
+	},
+	"squote": {
+		Description: "Wraps a value in single quotes, a single quote is escaped as two.",
+		Examples:    []string{` + "`" + `{{.Private.Password | squote}}` + "`" + `},
+	},
+	"repeat": {
+		Description: "Repeats a string a number of times.",
+		Examples:    []string{` + "`" + `{{"-" | repeat 20}}` + "`" + `},
+	},
+	"substr": {
+		Description: "Returns the characters from start to end, a negative end is the end of the string.",
+		Examples:    []string{` + "`" + `{{.Commit | substr 0 7}}` + "`" + `},
+	},
+	"truncate": {
+		Description: "Keeps the first characters of a string.",
+		Examples:    []string{` + "`" + `{{.Description | truncate 80}}` + "`" + `},
+	},
+	"wrap": {
+		Description: "Wraps the words of a string at a width.",
+		Examples:    []string{` + "`" + `{{.Description | wrap 80}}` + "`" + `},
+	},
+	"contains": {
+		Description: "Reports whether a string contains a substring.",
+		Examples:    []string{` + "`" + `{{if .Repo | contains "github.com"}}...{{end}}` + "`" + `},
+	},
+	"hasPrefix": {
+		Description: "Reports whether a string begins with a prefix.",
+		Examples:    []string{` + "`" + `{{if .Branch | hasPrefix "release/"}}...{{end}}` + "`" + `},
+	},
+	"hasSuffix": {
+		Description: "Reports whether a string ends with a suffix.",
+		Examples:    []string{` + "`" + `{{if .Repo | hasSuffix ".git"}}...{{end}}` + "`" + `},
+	},
+	"indent": {
+		Description: "Indents every line of a string by a number of spaces.",
+		Examples:    []string{` + "`" + `{{include "job" . | indent 4}}` + "`" + `},
+	},
+	"padLeft": {
+		Description: "Pads a string on the left to a width with spaces or an optional pad.",
+		Examples:    []string{` + "`" + `{{.Index | padLeft 5 "0"}}` + "`" + `},
+	},
+	"padRight": {
+		Description: "Pads a string on the right to a width with spaces or an optional pad.",
+		Examples:    []string{` + "`" + `{{.Name | padRight 20}}` + "`" + `},
+	},
+}
+`
+	PipesUuidGo = `package runtime
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+//uuidNamespaces are the predefined namespaces of RFC 4122 for uuidv5
+var uuidNamespaces = map[string]string{
+	"dns":  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	"url":  "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+	"oid":  "6ba7b812-9dad-11d1-80b4-00c04fd430c8",
+	"x500": "6ba7b814-9dad-11d1-80b4-00c04fd430c8",
+}
+
+//shortIdEncoding is the lowercase Crockford base32 alphabet without the ambiguous i, l, o and u
+var shortIdEncoding = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+
+const shortIdLen = 12
+
+//uuidPipes are helper functions for random and deterministic identifiers,
+//uuidv5 and shortId return the same value for the same input on every render
+func uuidPipes() template.FuncMap {
+	return template.FuncMap{
+		"uuidv4": func() (string, error) {
+			var u [16]byte
+			if _, err := rand.Read(u[:]); err != nil {
+				return "", err
+			}
+			return formatUUID(u, 4), nil
+		},
+		//e.g. uuidv5 "dns" "python.org"  => "886313e1-3b8a-5372-9b90-0c9aee199e5d",
+		//the namespace is dns, url, oid, x500 or an UUID
+		"uuidv5": func(namespace, name string) (string, error) {
+			ns, err := parseUUID(namespace)
+			if err != nil {
+				return "", err
+			}
+			h := sha1.New()
+			h.Write(ns[:])
+			h.Write([]byte(name))
+			var u [16]byte
+			copy(u[:], h.Sum(nil))
+			return formatUUID(u, 5), nil
+		},
+		//e.g. shortId "repo1"  => "ht9013b24gyh"
+		"shortId": func(seed string) (string, error) {
+			sum := sha256.Sum256([]byte(seed))
+			return shortIdEncoding.EncodeToString(sum[:])[:shortIdLen], nil
+		},
+	}
+}
+
+//formatUUID sets the version and the RFC 4122 variant bits of an UUID
+func formatUUID(u [16]byte, version byte) string {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if ns, ok := uuidNamespaces[strings.ToLower(s)]; ok {
+		s = ns
+	}
+	b, err := hex.DecodeString(strings.Replace(strings.Trim(s, "{}"), "-", "", -1))
+	if err != nil || len(b) != len(u) {
+		return u, fmt.Errorf("invalid UUID namespace %q (dns, url, oid, x500 or an UUID)", s)
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+var uuidPipesInfo = map[string]PipeInfo{
+	"uuidv4": {
+		Description: "Returns a random UUID, it changes on every render.",
+		Examples:    []string{` + "`" + `{{uuidv4}}` + "`" + `},
+	},
+	"uuidv5": {
+		Description: "Returns the UUID of a name in a namespace: dns, url, oid, x500 or an UUID, it is the same on every render.",
+		Examples:    []string{` + "`" + `{{uuidv5 "dns" "github.com"}}` + "`" + `, ` + "`" + `{{.Name | uuidv5 "6ba7b811-9dad-11d1-80b4-00c04fd430c8"}}` + "`" + `},
+	},
+	"shortId": {
+		Description: "Returns a 12 character id derived from a seed, it is the same on every render.",
+		Examples:    []string{` + "`" + `{{.Name | shortId}}` + "`" + `},
+	},
+}
+`
+	RenderGo = `package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//Template is a file of RenderTree and the options rendering it, Name is the templatable path of its output
+//relative to the output directory and a Copy is copied unchanged
+type Template struct {
+	File       string
+	Name       string
+	Copy       bool
+	LeftDelim  string
+	RightDelim string
+	Partials   []string
+	Engine     string
+	Format     string
+	Strict     bool
+}
+
+//renderTemplate renders a template file with the partials and the layouts it extends as main does for a single
+//template, with Strict it fails on missing keys and "<no value>" and adds the names the template references to names
+func (p *Pipes) renderTemplate(t Template, data interface{}, names map[string]bool) ([]byte, error) {
+	text, err := ioutil.ReadFile(t.File)
+	if err != nil {
+		return nil, fmt.Errorf("reading template file: %s", err.Error())
+	}
+	tmpl := template.New(t.File).Delims(t.LeftDelim, t.RightDelim).Funcs(p.Map)
+	options := []string{}
+	if t.Strict {
+		options = append(options, "missingkey=error")
+		tmpl.Option(options...)
+	}
+	for _, v := range t.Partials {
+		partial, err := ioutil.ReadFile(v)
+		if err != nil {
+			return nil, fmt.Errorf("reading partial file: %s", err.Error())
+		}
+		if _, err := tmpl.New(v).Parse(string(partial)); err != nil {
+			return nil, fmt.Errorf("parsing partial file: %s", err.Error())
+		}
+	}
+	if err := ParseLayout(tmpl, t.File, string(text), t.LeftDelim, t.RightDelim); err != nil {
+		return nil, fmt.Errorf("parsing template file: %s", err.Error())
+	}
+	p.SetTemplate(tmpl)
+	p.html = nil
+
+	var output bytes.Buffer
+	if t.Engine == "html" {
+		h, err := p.HTMLTemplate(tmpl, options...)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template file: %s", err.Error())
+		}
+		err = h.Execute(&output, data)
+	} else {
+		err = tmpl.Execute(&output, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("executing template output: %s", err.Error())
+	}
+	if t.Strict {
+		if err := CheckOutput(output.Bytes()); err != nil {
+			return nil, fmt.Errorf("executing template output: %s", err.Error())
+		}
+		templateNames(tmpl, names)
+	}
+	if err := CheckFormat(t.Format, output.Bytes()); err != nil {
+		return nil, fmt.Errorf("checking template output: %s", err.Error())
+	}
+	return output.Bytes(), nil
+}
+
+//RenderTree renders the templates and copies the other files into dir with their rendered names, the file
+//pipes of a template are relative to its directory and with Strict it returns the input fields of data that
+//no template references. The names, those of the copied files included, are templates in the delimiters of
+//the directory and two files cannot render the same name. Every file is rendered before any is written so
+//that a failing template writes nothing
+func (p *Pipes) RenderTree(dir, leftDelim, rightDelim string, files []Template, data interface{}) ([]string, error) {
+	type output struct {
+		file    string
+		content []byte
+		mode    os.FileMode
+	}
+	outputs := []output{}
+	sources := make(map[string]string)
+	names := make(map[string]bool)
+	strict := false
+	for _, v := range files {
+		name, err := p.renderName(v.Name, leftDelim, rightDelim, data)
+		if err != nil {
+			return nil, err
+		}
+		if source, ok := sources[name]; ok {
+			return nil, fmt.Errorf("%s and %s both render %s", source, v.File, filepath.ToSlash(name))
+		}
+		sources[name] = v.File
+		fi, err := os.Stat(v.File)
+		if err != nil {
+			return nil, err
+		}
+		var content []byte
+		if v.Copy {
+			content, err = ioutil.ReadFile(v.File)
+		} else {
+			p.fileDir = filepath.Dir(v.File)
+			content, err = p.renderTemplate(v, data, names)
+			strict = strict || v.Strict
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", v.File, err.Error())
+		}
+		outputs = append(outputs, output{filepath.Join(dir, name), content, fi.Mode().Perm()})
+	}
+	for _, v := range outputs {
+		if err := os.MkdirAll(filepath.Dir(v.file), 0777); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(v.file, v.content, v.mode); err != nil {
+			return nil, err
+		}
+	}
+	if !strict {
+		return []string{}, nil
+	}
+	return unusedFields(names, data), nil
+}
+
+//renderName renders the output path of a file, it cannot be outside of the output directory
+func (p *Pipes) renderName(text, leftDelim, rightDelim string, data interface{}) (string, error) {
+	tmpl, err := template.New(text).Delims(leftDelim, rightDelim).Funcs(p.Map).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing file name %s: %s", text, err.Error())
+	}
+	var name bytes.Buffer
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("executing file name %s: %s", text, err.Error())
+	}
+	clean := filepath.Clean(filepath.FromSlash(name.String()))
+	if clean == "." || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file name %s renders %q outside of the output directory", text, name.String())
+	}
+	return clean, nil
+}
+`
+	StrictGo = `package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+//noValue is the output of text/template for a missing map key without missingkey=error
+const noValue = "<no value>"
+
+//CheckOutput returns an error for the first line of a rendered output containing "<no value>"
+func CheckOutput(output []byte) error {
+	for k, v := range bytes.Split(output, []byte("\n")) {
+		if bytes.Contains(v, []byte(noValue)) {
+			return fmt.Errorf("strict: line %d renders %q: %s", k+1, noValue, strings.TrimSpace(string(v)))
+		}
+	}
+	return nil
+}
+
+//UnusedFields returns the inputs and the input fields e.g. "Private.Password" of a data struct whose names
+//are not referenced by the templates of t, a name is referenced as a field e.g. .Password or as a string
+//e.g. index .Private "Password" so the fields reached through the pipes or tpl are not detected
+func UnusedFields(t *template.Template, data interface{}) []string {
+	names := make(map[string]bool)
+	templateNames(t, names)
+	return unusedFields(names, data)
+}
+
+//templateNames adds the field names and the strings of the templates of t to names
+func templateNames(t *template.Template, names map[string]bool) {
+	for _, v := range t.Templates() {
+		if v.Tree != nil {
+			fieldNames(v.Tree.Root, names)
+		}
+	}
+}
+
+//unusedFields returns the inputs and the input fields of data whose names are not in names
+func unusedFields(names map[string]bool, data interface{}) []string {
+	unused := []string{}
+	rv := reflect.ValueOf(indirect(data))
+	if rv.Kind() != reflect.Struct {
+		return unused
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		input := rv.Type().Field(i)
+		if input.PkgPath != "" {
+			continue
+		}
+		if !names[input.Name] {
+			unused = append(unused, input.Name)
+			continue
+		}
+		for _, v := range inputFields(rv.Field(i).Interface()) {
+			if !names[v] {
+				unused = append(unused, input.Name+"."+v)
+			}
+		}
+	}
+	return unused
+}
+
+//inputFields returns the exported fields of a struct or the sorted string keys of a map
+func inputFields(v interface{}) []string {
+	fields := []string{}
+	rv := reflect.ValueOf(indirect(v))
+	switch rv.Kind() {
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.PkgPath == "" {
+				fields = append(fields, f.Name)
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		for _, k := range rv.MapKeys() {
+			fields = append(fields, k.String())
+		}
+		sort.Strings(fields)
+	}
+	return fields
+}
+
+//fieldNames adds the field names and the strings of a parse tree to names
+func fieldNames(node parse.Node, names map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Nodes {
+			fieldNames(v, names)
+		}
+	case *parse.ActionNode:
+		fieldNames(n.Pipe, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Cmds {
+			fieldNames(v, names)
+		}
+	case *parse.CommandNode:
+		for _, v := range n.Args {
+			fieldNames(v, names)
+		}
+	case *parse.FieldNode:
+		for _, v := range n.Ident {
+			names[v] = true
+		}
+	case *parse.VariableNode:
+		for _, v := range n.Ident[1:] {
+			names[v] = true
+		}
+	case *parse.ChainNode:
+		fieldNames(n.Node, names)
+		for _, v := range n.Field {
+			names[v] = true
+		}
+	case *parse.StringNode:
+		names[n.Text] = true
+	case *parse.IfNode:
+		fieldNames(&n.BranchNode, names)
+	case *parse.RangeNode:
+		fieldNames(&n.BranchNode, names)
+	case *parse.WithNode:
+		fieldNames(&n.BranchNode, names)
+	case *parse.BranchNode:
+		fieldNames(n.Pipe, names)
+		fieldNames(n.List, names)
+		fieldNames(n.ElseList, names)
+	case *parse.TemplateNode:
+		fieldNames(n.Pipe, names)
+	}
+}
+`
+)
+
+// RuntimePipes is the list of embedded runtime files that define the default pipes
+var RuntimePipes = []string{BcryptGo, BlowfishGo, FormatGo, LayoutGo, PipesGo, PipesConvertGo, PipesCryptoGo, PipesDateGo, PipesDictGo, PipesEnvGo, PipesFileGo, PipesHtmlGo, PipesIncludeGo, PipesMathGo, PipesMissingGo, PipesNetworkGo, PipesPathGo, PipesRegexGo, PipesSemverGo, PipesStringsGo, PipesUuidGo, RenderGo, StrictGo}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	bcryptCost = 10
+	//blowfish only expands the first 72 bytes of the key
+	bcryptMaxPassword = 72
+)
+
+var bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+//bcryptHash returns the $2a$ hash of the password with a random salt
+func bcryptHash(password string) (string, error) {
+	if len(password) > bcryptMaxPassword {
+		return "", fmt.Errorf("bcrypt: password is longer than %d bytes", bcryptMaxPassword)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("bcrypt: %s", err.Error())
+	}
+	return bcryptWithSalt([]byte(password), bcryptCost, salt), nil
+}
+
+//bcryptWithSalt is the eksblowfish setup followed by 64 encryptions of the magic text
+func bcryptWithSalt(password []byte, cost uint, salt []byte) string {
+	//C implementations include the trailing NUL of the password in the key
+	key := append(password[:len(password):len(password)], 0)
+	c := newBlowfish()
+	c.expand(key, salt)
+	for i := uint64(0); i < 1<<cost; i++ {
+		c.expand(key, nil)
+		c.expand(salt, nil)
+	}
+	text := []byte("OrpheanBeholderScryDoubt")
+	for i := 0; i < len(text); i += 8 {
+		l, r := binary.BigEndian.Uint32(text[i:]), binary.BigEndian.Uint32(text[i+4:])
+		for j := 0; j < 64; j++ {
+			l, r = c.encrypt(l, r)
+		}
+		binary.BigEndian.PutUint32(text[i:], l)
+		binary.BigEndian.PutUint32(text[i+4:], r)
+	}
+	//C implementations only encode 23 of the 24 bytes
+	return fmt.Sprintf("$2a$%02d$%s%s", cost, bcryptEncoding.EncodeToString(salt), bcryptEncoding.EncodeToString(text[:23]))
+}
+
+type blowfish struct {
+	p [18]uint32
+	s [4][256]uint32
+}
+
+func newBlowfish() *blowfish {
+	return &blowfish{
+		p: blowfishP,
+		s: [4][256]uint32{blowfishS0, blowfishS1, blowfishS2, blowfishS3},
+	}
+}
+
+func (c *blowfish) f(x uint32) uint32 {
+	return ((c.s[0][byte(x>>24)] + c.s[1][byte(x>>16)]) ^ c.s[2][byte(x>>8)]) + c.s[3][byte(x)]
+}
+
+func (c *blowfish) encrypt(l, r uint32) (uint32, uint32) {
+	l ^= c.p[0]
+	for i := 1; i < 17; i += 2 {
+		r ^= c.f(l) ^ c.p[i]
+		l ^= c.f(r) ^ c.p[i+1]
+	}
+	return r ^ c.p[17], l
+}
+
+//expand mixes the key into the subkeys, the salt is only mixed in by the first expansion
+func (c *blowfish) expand(key, salt []byte) {
+	j := 0
+	for i := range c.p {
+		c.p[i] ^= blowfishWord(key, &j)
+	}
+	j = 0
+	var l, r uint32
+	for _, box := range [][]uint32{c.p[:], c.s[0][:], c.s[1][:], c.s[2][:], c.s[3][:]} {
+		for i := 0; i < len(box); i += 2 {
+			if salt != nil {
+				l ^= blowfishWord(salt, &j)
+				r ^= blowfishWord(salt, &j)
+			}
+			l, r = c.encrypt(l, r)
+			box[i], box[i+1] = l, r
+		}
+	}
+}
+
+//blowfishWord reads the next 4 bytes of b as a word, wrapping around at the end of b
+func blowfishWord(b []byte, pos *int) uint32 {
+	var w uint32
+	for i := 0; i < 4; i++ {
+		w = w<<8 | uint32(b[*pos])
+		*pos = (*pos + 1) % len(b)
+	}
+	return w
+}
@@ -0,0 +1,25 @@
+package runtime
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+//bcrypt is unexported and salted at random, so the known vectors of the C implementations are checked from within the package
+var _ = Describe("Bcrypt", func() {
+	vectors := []struct {
+		password, hash string
+	}{
+		{"U*U", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+		{"U*U*", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.VGOzA784oUp/Z0DY336zx7pLYAy0lwK"},
+		{"U*U*U", "$2a$05$XXXXXXXXXXXXXXXXXXXXXOAcXxm9kjPGEMsLznoKqmqw7tc8WCx4a"},
+		{"", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.7uG0VCzI2bS7j6ymqJi9CdcdxiRTWNy"},
+	}
+	It("should match the known vectors", func() {
+		for _, v := range vectors {
+			salt, err := bcryptEncoding.DecodeString(v.hash[7:29])
+			Expect(err).To(BeNil())
+			Expect(bcryptWithSalt([]byte(v.password), 5, salt)).To(Equal(v.hash))
+		}
+	})
+})
@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+//CheckFormat returns an error when a rendered output is not well-formed "json" or "xml",
+//any other format is not checked
+func CheckFormat(format string, output []byte) error {
+	switch format {
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal(output, &v); err != nil {
+			return fmt.Errorf("format: output is not valid json: %s", err.Error())
+		}
+	case "xml":
+		d := xml.NewDecoder(bytes.NewReader(output))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("format: output is not well-formed xml: %s", err.Error())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package runtime_test
+
+import (
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+	Context("when validating CheckFormat method", func() {
+		It("should accept a well-formed output", func() {
+			Expect(CheckFormat("json", []byte(`{"name": "repo1", "ports": [8080]}`))).To(BeNil())
+			Expect(CheckFormat("xml", []byte(`<?xml version="1.0"?><repos><repo name="repo1"/></repos>`))).To(BeNil())
+			Expect(CheckFormat("text", []byte(`{"name":`))).To(BeNil())
+		})
+		It("should catch invalid json", func() {
+			err := CheckFormat("json", []byte(`{"name": "repo1",}`))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("output is not valid json"))
+		})
+		It("should catch malformed xml", func() {
+			err := CheckFormat("xml", []byte(`<repos><repo></repos>`))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("output is not well-formed xml"))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+//ParseLayout parses the text of a template file into t. A template can extend a base template with
+//{{/* extends "base.yml" */}} at its beginning, after the front-matter or the delims header if any, the path is
+//relative to the template and an extends comment anywhere else is an error. The base template
+//is parsed first and the template can only override the blocks the base templates define, e.g.
+//{{define "resources"}}...{{end}} replaces the {{block "resources" .}}...{{end}} of the base, or add
+//blocks in its overrides for the templates extending it.
+//Empty delimiters are the default "{{" and "}}"
+func ParseLayout(t *template.Template, file, text, leftDelim, rightDelim string) error {
+	l := layout{t: t, leftDelim: leftDelim, rightDelim: rightDelim}
+	_, err := l.parse(file, text, nil)
+	return err
+}
+
+type layout struct {
+	t                     *template.Template
+	leftDelim, rightDelim string
+}
+
+//parse returns the blocks defined by the template and every base template of its chain
+func (l layout) parse(file, text string, children []string) (map[string]bool, error) {
+	for _, v := range children {
+		if v == file {
+			return nil, fmt.Errorf("layout: %s extends itself through %s", file, strings.Join(children, ", "))
+		}
+	}
+	trees, err := l.trees(file, text)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make(map[string]bool)
+	for k := range trees {
+		if k != file {
+			blocks[k] = true
+		}
+	}
+	base, ok := l.extends(text)
+	if !ok && anyExtends.MatchString(text) {
+		return nil, fmt.Errorf("layout: %s has an extends comment that is not at its beginning or not in its delimiters", file)
+	}
+	if !ok {
+		if file == l.t.Name() {
+			_, err = l.t.Parse(text)
+			return blocks, err
+		}
+		nt, err := l.t.New(file).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		_, err = l.t.AddParseTree(l.t.Name(), nt.Tree)
+		return blocks, err
+	}
+
+	if root, ok := trees[file]; ok && !parse.IsEmptyTree(root.Root) {
+		return nil, fmt.Errorf("layout: %s extends %s and can only define blocks", file, base)
+	}
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(filepath.Dir(file), base)
+	}
+	data, err := ioutil.ReadFile(base)
+	if err != nil {
+		return nil, fmt.Errorf("layout: %s", err.Error())
+	}
+	baseBlocks, err := l.parse(base, string(data), append(children, file))
+	if err != nil {
+		return nil, err
+	}
+	//a block of the template is either an override or a new block it calls, e.g. in the blocks it overrides
+	calls := make(map[string]bool)
+	for _, v := range trees {
+		templateCalls(v.Root, calls)
+	}
+	for k := range blocks {
+		if !baseBlocks[k] && !calls[k] {
+			return nil, fmt.Errorf("layout: %s overrides block %q that %s does not define", file, k, base)
+		}
+	}
+	for k := range baseBlocks {
+		blocks[k] = true
+	}
+	if file == l.t.Name() {
+		_, err = l.t.Parse(text)
+	} else {
+		_, err = l.t.New(file).Parse(text)
+	}
+	return blocks, err
+}
+
+//templateCalls adds the names of the templates called by a node
+func templateCalls(node parse.Node, calls map[string]bool) {
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		calls[n.Name] = true
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Nodes {
+			templateCalls(v, calls)
+		}
+	case *parse.IfNode:
+		templateCalls(n.List, calls)
+		templateCalls(n.ElseList, calls)
+	case *parse.RangeNode:
+		templateCalls(n.List, calls)
+		templateCalls(n.ElseList, calls)
+	case *parse.WithNode:
+		templateCalls(n.List, calls)
+		templateCalls(n.ElseList, calls)
+	}
+}
+
+//trees parses a template without checking its functions and returns its defined templates by name
+func (l layout) trees(file, text string) (map[string]*parse.Tree, error) {
+	tree := parse.New(file)
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(text, l.leftDelim, l.rightDelim, trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+//anyExtends is an extends comment anywhere in a template and in any delimiters
+var anyExtends = regexp.MustCompile(`/\*\s*extends\s+"[^"]+"\s*\*/`)
+
+//extends returns the base template named at the beginning of a template, after the other comments
+//there e.g. a delims header or the front-matter
+func (l layout) extends(text string) (string, bool) {
+	left, right := l.leftDelim, l.rightDelim
+	if left == "" {
+		left = "{{"
+	}
+	if right == "" {
+		right = "}}"
+	}
+	left, right = regexp.QuoteMeta(left), regexp.QuoteMeta(right)
+	comment := `\s*` + left + `-?\s*/\*(?:[^*]|\*[^/])*\*/\s*-?` + right
+	re := regexp.MustCompile(`^(?:` + comment + `)*?\s*` + left + `-?\s*/\*\s*extends\s+"([^"]+)"\s*\*/\s*-?` + right)
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
@@ -0,0 +1,151 @@
+package runtime_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Layout", func() {
+	var (
+		pipes   *Pipes
+		buffer  *gbytes.Buffer
+		tmplDir string
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		buffer = gbytes.NewBuffer()
+		tmplDir, _ = ioutil.TempDir(os.TempDir(), "")
+		files := map[string]string{
+			"base.yml": `name: {{.}}
+resources:
+{{- block "resources" .}} []{{end}}
+jobs:
+{{- block "jobs" .}} []{{end}}
+`,
+			"staging.yml": `{{/* extends "base.yml" */}}
+{{define "jobs"}}
+- name: {{. | toUpper}}-staging{{end}}
+`,
+			"staging-eu.yml": `{{- /* extends "staging.yml" */ -}}
+{{define "resources"}}
+- region: eu{{end}}`,
+		}
+		for k, v := range files {
+			Expect(ioutil.WriteFile(filepath.Join(tmplDir, k), []byte(v), 0666)).To(Succeed())
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(tmplDir)
+	})
+	render := func(name, text string) error {
+		file := filepath.Join(tmplDir, name)
+		if text == "" {
+			data, err := ioutil.ReadFile(file)
+			Expect(err).To(BeNil())
+			text = string(data)
+		}
+		tmpl := template.New(file).Funcs(pipes.Map)
+		if err := ParseLayout(tmpl, file, text, "", ""); err != nil {
+			return err
+		}
+		return tmpl.Execute(buffer, "repo1")
+	}
+
+	It("should render a template without a base", func() {
+		err := render("base.yml", "")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`name: repo1\nresources: \[\]\njobs: \[\]\n`))
+	})
+	It("should override the blocks of the base", func() {
+		err := render("staging.yml", "")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`name: repo1\nresources: \[\]\njobs:\n- name: REPO1-staging\n`))
+	})
+	It("should override the blocks of the base of the base", func() {
+		err := render("staging-eu.yml", "")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`name: repo1\nresources:\n- region: eu\njobs:\n- name: REPO1-staging\n`))
+	})
+	It("should override the blocks introduced by every level of the chain", func() {
+		files := map[string]string{
+			"release.yml": `{{/* extends "base.yml" */}}
+{{define "jobs"}}
+- name: {{.}}-release
+  steps:{{block "steps" .}} []{{end}}{{end}}`,
+			"release-eu.yml": `{{/* extends "release.yml" */}}
+{{define "steps"}}
+  - deploy: eu{{end}}
+{{define "resources"}}
+- region: eu{{end}}`,
+		}
+		for k, v := range files {
+			Expect(ioutil.WriteFile(filepath.Join(tmplDir, k), []byte(v), 0666)).To(Succeed())
+		}
+		err := render("release-eu.yml", "")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`name: repo1\nresources:\n- region: eu\njobs:\n- name: repo1-release\n  steps:\n  - deploy: eu\n`))
+	})
+	It("should extend a base after a delims header", func() {
+		base := filepath.Join(tmplDir, "dbase.yml")
+		Expect(ioutil.WriteFile(base, []byte(`[[/* delims "[[" "]]" */]]run: ${{ github.sha }}[[block "steps" .]] []]][[end]]`), 0666)).To(Succeed())
+		file := filepath.Join(tmplDir, "dstaging.yml")
+		tmpl := template.New(file).Delims("[[", "]]").Funcs(pipes.Map)
+		err := ParseLayout(tmpl, file, `[[/* delims "[[" "]]" */]]
+[[- /* extends "dbase.yml" */ -]]
+[[define "steps"]] [[.]][[end]]`, "[[", "]]")
+		Expect(err).To(BeNil())
+		Expect(tmpl.Execute(buffer, "repo1")).To(Succeed())
+		Eventually(buffer).Should(gbytes.Say(`^run: \$\{\{ github.sha \}\} repo1$`))
+	})
+	It("should extend a base after the front-matter", func() {
+		err := render("prod.yml", `{{- /* goflat
+inputs: inputs/repos.go
+*/ -}}
+{{- /* extends "staging.yml" */ -}}
+{{define "resources"}}
+- region: us{{end}}`)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`name: repo1\nresources:\n- region: us\njobs:\n- name: REPO1-staging\n`))
+	})
+	Context("with an invalid layout", func() {
+		It("should catch an unknown block", func() {
+			err := render("prod.yml", `{{/* extends "base.yml" */}}{{define "resource"}}{{end}}`)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`overrides block "resource" that`))
+			Expect(err.Error()).To(ContainSubstring(`base.yml does not define`))
+		})
+		It("should catch content outside of the blocks", func() {
+			err := render("prod.yml", `{{/* extends "base.yml" */}}name: {{.}}`)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`can only define blocks`))
+		})
+		It("should catch an extends comment that is not honoured", func() {
+			for _, v := range []string{
+				"name: {{.}}\n{{/* extends \"base.yml\" */}}",
+				"[[/* extends \"base.yml\" */]]{{define \"jobs\"}}{{end}}",
+			} {
+				err := render("prod.yml", v)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(`has an extends comment that is not at its beginning`))
+			}
+		})
+		It("should catch a missing base", func() {
+			err := render("prod.yml", `{{/* extends "missing.yml" */}}`)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`missing.yml`))
+		})
+		It("should catch a cycle", func() {
+			err := render("base.yml", `{{/* extends "staging.yml" */}}`)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`extends itself`))
+		})
+	})
+})
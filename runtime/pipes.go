@@ -1,61 +1,340 @@
 package runtime
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
 	"reflect"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 )
 
+const (
+	SourceDefault = "default"
+	SourceCustom  = "custom"
+
+	//OverrideAllow silently replaces the default pipes
+	OverrideAllow = "allow"
+	//OverrideWarn reports the undeclared overrides of the default pipes
+	OverrideWarn = "warn"
+	//OverrideError fails on the undeclared overrides of the default pipes
+	OverrideError = "error"
+)
+
+//PipeInfo describes a pipe, Name, Signature and Source are filled in when the pipe is added
+type PipeInfo struct {
+	Name        string   `json:"name"`
+	Signature   string   `json:"signature"`
+	Description string   `json:"description,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	Deprecated  string   `json:"deprecated,omitempty"`
+	Source      string   `json:"source"`
+	Provider    string   `json:"provider,omitempty"`
+	Overrides   bool     `json:"overrides,omitempty"`
+}
+
+//Provider is a named function of the custom pipes
+type Provider struct {
+	Name  string
+	Pipes template.FuncMap
+}
+
 type Pipes struct {
-	Map template.FuncMap
+	Map  template.FuncMap
+	Info map[string]PipeInfo
+
+	regexps regexCache
+	clock   time.Time
+	tmpl    *template.Template
+	depth   int
+
+	html        *htmltemplate.Template
+	htmlOptions []string
+
+	fileDir, fileRoot string
+	envAllow          []string
 }
 
+//Extend adds custom pipes, overriding the default pipes with the same name
 func (p *Pipes) Extend(fm template.FuncMap) {
+	p.add(SourceCustom, fm, nil)
+}
+
+//Override merges the custom pipes of the providers in order and applies the override policy to the default pipes
+//they replace, declared lists the intended overrides. A later provider replacing the pipe of an earlier provider is
+//reported the same way. The returned warnings are the problems found under OverrideWarn
+func (p *Pipes) Override(providers []Provider, declared []string, policy string) ([]string, error) {
+	if policy != OverrideAllow && policy != OverrideWarn && policy != OverrideError {
+		return nil, fmt.Errorf("unknown override policy %q (allow, warn or error)", policy)
+	}
+	intended := make(map[string]bool, len(declared))
+	for _, v := range declared {
+		intended[v] = true
+	}
+	var problems []string
+	merged := make(map[string]string)
+	for _, provider := range providers {
+		for _, k := range sortedFuncs(provider.Pipes) {
+			if earlier, ok := merged[k]; ok {
+				problems = append(problems, fmt.Sprintf("pipe %q of %s replaces the one of %s", k, provider.Name, earlier))
+			} else if i, ok := p.Info[k]; ok && i.Source == SourceDefault && !intended[k] {
+				problems = append(problems, fmt.Sprintf("pipe %q of %s overrides a default pipe without being declared in CustomPipesOverrides", k, provider.Name))
+			}
+			merged[k] = provider.Name
+		}
+	}
+	for _, v := range declared {
+		if i, ok := p.Info[v]; !ok || i.Source != SourceDefault {
+			problems = append(problems, fmt.Sprintf("pipe %q is declared as an override but there is no default pipe to override", v))
+		} else if _, ok := merged[v]; !ok {
+			problems = append(problems, fmt.Sprintf("pipe %q is declared as an override but is not defined", v))
+		}
+	}
+	if len(problems) > 0 && policy == OverrideError {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	for _, provider := range providers {
+		p.add(SourceCustom, provider.Pipes, nil)
+		for k := range provider.Pipes {
+			i := p.Info[k]
+			i.Provider = provider.Name
+			p.Info[k] = i
+		}
+	}
+	if policy == OverrideWarn {
+		return problems, nil
+	}
+	return nil, nil
+}
+
+//Overridden returns the sorted names of the default pipes replaced by custom pipes
+func (p *Pipes) Overridden() []string {
+	out := []string{}
+	for _, v := range p.List() {
+		if v.Overrides {
+			out = append(out, v.Name)
+		}
+	}
+	return out
+}
+
+//Describe adds the description, examples and deprecation of already defined pipes
+func (p *Pipes) Describe(info map[string]PipeInfo) error {
+	for k, v := range info {
+		i, ok := p.Info[k]
+		if !ok {
+			return fmt.Errorf("cannot describe undefined pipe %q", k)
+		}
+		i.Description, i.Examples, i.Deprecated = v.Description, v.Examples, v.Deprecated
+		p.Info[k] = i
+	}
+	return nil
+}
+
+//DescribeCustom adds the descriptions of CustomPipesInfo, a plain map so that the custom pipes build without the runtime
+func (p *Pipes) DescribeCustom(info map[string]struct {
+	Description string
+	Examples    []string
+	Deprecated  string
+}) error {
+	out := make(map[string]PipeInfo, len(info))
+	for k, v := range info {
+		out[k] = PipeInfo{Description: v.Description, Examples: v.Examples, Deprecated: v.Deprecated}
+	}
+	return p.Describe(out)
+}
+
+//List returns the information of every pipe sorted by name
+func (p *Pipes) List() []PipeInfo {
+	out := make([]PipeInfo, 0, len(p.Info))
+	for _, v := range p.Info {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+//Print writes the list of pipes in "text" or "json" format
+func (p *Pipes) Print(w io.Writer, format string) error {
+	list := p.List()
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	case "text":
+		for _, v := range list {
+			source := v.Source
+			if v.Provider != "" {
+				source += " " + v.Provider
+			}
+			if v.Overrides {
+				source += ", overrides " + SourceDefault
+			}
+			fmt.Fprintf(w, "%s %s [%s]\n", v.Name, v.Signature, source)
+			if v.Deprecated != "" {
+				fmt.Fprintf(w, "    DEPRECATED: %s\n", v.Deprecated)
+			}
+			if v.Description != "" {
+				fmt.Fprintf(w, "    %s\n", v.Description)
+			}
+			for _, e := range v.Examples {
+				fmt.Fprintf(w, "    e.g. %s\n", e)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q (text or json)", format)
+}
+
+func (p *Pipes) add(source string, fm template.FuncMap, info map[string]PipeInfo) {
 	for k, v := range fm {
+		i := info[k]
+		i.Name, i.Signature, i.Source = k, reflect.TypeOf(v).String(), source
+		if old, ok := p.Info[k]; ok && source == SourceCustom {
+			i.Overrides = old.Source == SourceDefault || old.Overrides
+		}
 		p.Map[k] = v
+		p.Info[k] = i
 	}
 }
 
+func sortedFuncs(fm template.FuncMap) []string {
+	keys := make([]string, 0, len(fm))
+	for k := range fm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func NewPipes() *Pipes {
-	return &Pipes{
-		Map: template.FuncMap{
-			"join": func(sep string, a []string) (string, error) {
-				return strings.Join(a, sep), nil
-			},
-			//e.g. map "Name,Age,Job" "|"  => "[John|25|Painter Jane|21|Teacher]"
-			"map": func(f, sep string, a interface{}) ([]string, error) {
-				fields := strings.Split(f, ",")
-				reflectedArray := reflect.ValueOf(a)
-				out := make([]string, reflectedArray.Len())
-				i := 0
-				for i < len(out) {
-					v := reflectedArray.Index(i)
-					row := make([]string, len(fields))
-					for k, field := range fields {
-						row[k] = v.FieldByName(field).String()
+	p := &Pipes{
+		Map:  template.FuncMap{},
+		Info: make(map[string]PipeInfo),
+	}
+	p.add(SourceDefault, basicPipes(), basicPipesInfo)
+	p.add(SourceDefault, stringPipes(), stringPipesInfo)
+	p.add(SourceDefault, convertPipes(), convertPipesInfo)
+	p.add(SourceDefault, missingPipes(), missingPipesInfo)
+	p.add(SourceDefault, cryptoPipes(), cryptoPipesInfo)
+	p.add(SourceDefault, uuidPipes(), uuidPipesInfo)
+	p.add(SourceDefault, p.regexPipes(), regexPipesInfo)
+	p.add(SourceDefault, p.datePipes(), datePipesInfo)
+	p.add(SourceDefault, networkPipes(), networkPipesInfo)
+	p.add(SourceDefault, mathPipes(), mathPipesInfo)
+	p.add(SourceDefault, semverPipes(), semverPipesInfo)
+	p.add(SourceDefault, pathPipes(), pathPipesInfo)
+	p.add(SourceDefault, dictPipes(), dictPipesInfo)
+	p.add(SourceDefault, p.includePipes(), includePipesInfo)
+	p.add(SourceDefault, htmlPipes(), htmlPipesInfo)
+	p.add(SourceDefault, p.filePipes(), filePipesInfo)
+	p.add(SourceDefault, p.envPipes(), envPipesInfo)
+	return p
+}
+
+//basicPipes are the helper functions for lists and strings, the piped values are converted
+//with toString and toStrings so that e.g. a []interface{} of decoded JSON can be joined
+func basicPipes() template.FuncMap {
+	return template.FuncMap{
+		"join": func(sep string, a interface{}) (string, error) {
+			return strings.Join(toStrings(a), sep), nil
+		},
+		//e.g. map "Name,Age,Job" "|"  => "[John|25|Painter Jane|21|Teacher]"
+		"map": func(f, sep string, a interface{}) ([]string, error) {
+			fields := strings.Split(f, ",")
+			reflectedArray := reflect.ValueOf(indirect(a))
+			if reflectedArray.Kind() != reflect.Slice && reflectedArray.Kind() != reflect.Array {
+				return nil, fmt.Errorf("map: cannot iterate over %T", a)
+			}
+			out := make([]string, reflectedArray.Len())
+			for i := range out {
+				row := make([]string, len(fields))
+				for k, field := range fields {
+					v, err := fieldOf(reflectedArray.Index(i), field)
+					if err != nil {
+						return nil, err
 					}
-					out[i] = strings.Join(row, sep)
-					i++
+					row[k] = toString(v)
 				}
-				return out, nil
-			},
-			"replace": func(old, new, s string) (string, error) {
-				//replace all occurrences of a value
-				return strings.Replace(s, old, new, -1), nil
-			},
-			"split": func(sep, s string) ([]string, error) {
-				s = strings.TrimSpace(s)
-				if s == "" {
-					return []string{}, nil
-				}
-				return strings.Split(s, sep), nil
-			},
-			"toUpper": func(s string) (string, error) {
-				return strings.ToUpper(s), nil
-			},
-			"toLower": func(s string) (string, error) {
-				return strings.ToLower(s), nil
-			},
+				out[i] = strings.Join(row, sep)
+			}
+			return out, nil
+		},
+		"replace": func(old, new string, s interface{}) (string, error) {
+			//replace all occurrences of a value
+			return strings.Replace(toString(s), old, new, -1), nil
+		},
+		"split": func(sep string, v interface{}) ([]string, error) {
+			s := strings.TrimSpace(toString(v))
+			if s == "" {
+				return []string{}, nil
+			}
+			return strings.Split(s, sep), nil
+		},
+		"toUpper": func(s interface{}) (string, error) {
+			return strings.ToUpper(toString(s)), nil
+		},
+		"toLower": func(s interface{}) (string, error) {
+			return strings.ToLower(toString(s)), nil
 		},
 	}
 }
+
+//fieldOf returns the field of a struct or the key of a map with string keys
+func fieldOf(v reflect.Value, name string) (interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			return nil, fmt.Errorf("map: %s has no field %q", v.Type(), name)
+		}
+		return f.Interface(), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			f := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !f.IsValid() {
+				return nil, nil
+			}
+			return f.Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("map: cannot read field %q of %s", name, v.Type())
+}
+
+var basicPipesInfo = map[string]PipeInfo{
+	"join": {
+		Description: "Joins a list of strings with a separator.",
+		Examples:    []string{`{{.List | join ","}}`},
+	},
+	"map": {
+		Description: "Joins the comma separated fields of every object in a list with a separator.",
+		Examples:    []string{`{{.ListOfObjects | map "Name,Age" ","}}`},
+	},
+	"replace": {
+		Description: "Replaces all occurrences of a value.",
+		Examples:    []string{`{{.StringValue | replace "," " "}}`},
+	},
+	"split": {
+		Description: "Splits a string by a separator, an empty string is an empty list.",
+		Examples:    []string{`{{.StringValue | split ","}}`},
+	},
+	"toLower": {
+		Description: "Converts a string to lower case.",
+		Examples:    []string{`{{.Field | toLower}}`},
+	},
+	"toUpper": {
+		Description: "Converts a string to upper case.",
+		Examples:    []string{`{{.Field | toUpper}}`},
+	},
+}
@@ -0,0 +1,188 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//convertPipes are helper functions for converting and inspecting values e.g. the
+//[]interface{} and float64 values of decoded JSON
+func convertPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. toString 8080  => "8080", nil is an empty string
+		"toString": func(v interface{}) (string, error) {
+			return toString(v), nil
+		},
+		//e.g. toInt "8080" or toInt 8080.0  => 8080
+		"toInt": func(v interface{}) (int, error) {
+			return toInt(v)
+		},
+		"toFloat": func(v interface{}) (float64, error) {
+			return toFloat64(indirect(v))
+		},
+		//e.g. toBool "yes" is an error, toBool "true", toBool "1" and toBool 1  => true
+		"toBool": func(v interface{}) (bool, error) {
+			return toBool(v)
+		},
+		//e.g. toStrings .Ports  => ["8080" "8443"]
+		"toStrings": func(v interface{}) ([]string, error) {
+			return toStrings(v), nil
+		},
+		//e.g. kindOf .Ports  => "slice"
+		"kindOf": func(v interface{}) (string, error) {
+			return reflect.ValueOf(v).Kind().String(), nil
+		},
+		//e.g. typeOf .Ports  => "[]int"
+		"typeOf": func(v interface{}) (string, error) {
+			return fmt.Sprintf("%T", v), nil
+		},
+		"kindIs": func(kind string, v interface{}) (bool, error) {
+			return reflect.ValueOf(v).Kind().String() == kind, nil
+		},
+	}
+}
+
+//indirect returns the value a pointer points to, nil for a nil pointer
+func indirect(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+//toString converts any value to its string form, nil is an empty string
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case fmt.Stringer:
+		return s.String()
+	case error:
+		return s.Error()
+	}
+	v = indirect(v)
+	if v == nil {
+		return ""
+	}
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+//toStrings converts every element of a slice or an array to a string, any other value is a list of one string
+func toStrings(v interface{}) []string {
+	v = indirect(v)
+	if v == nil {
+		return []string{}
+	}
+	if s, ok := v.([]string); ok {
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []string{toString(v)}
+	}
+	out := make([]string, rv.Len())
+	for i := range out {
+		out[i] = toString(rv.Index(i).Interface())
+	}
+	return out
+}
+
+func toInt(v interface{}) (int, error) {
+	v = indirect(v)
+	switch b := v.(type) {
+	case nil:
+		return 0, nil
+	case bool:
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		s := strings.TrimSpace(b)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			v = n
+		} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+			v = f
+		} else {
+			return 0, fmt.Errorf("cannot convert %q to int", b)
+		}
+	}
+	n, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt || n < math.MinInt {
+		return 0, errOverflow
+	}
+	return int(n), nil
+}
+
+func toBool(v interface{}) (bool, error) {
+	v = indirect(v)
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(b))
+	}
+	f, err := toFloat64(v)
+	if err != nil {
+		return false, fmt.Errorf("cannot convert %T to bool", v)
+	}
+	return f != 0, nil
+}
+
+var convertPipesInfo = map[string]PipeInfo{
+	"toString": {
+		Description: "Converts a value to a string, nil is an empty string.",
+		Examples:    []string{`{{.Port | toString}}`},
+	},
+	"toInt": {
+		Description: "Converts a number, a numeric string or a bool to an integer.",
+		Examples:    []string{`{{.Port | toInt}}`},
+	},
+	"toFloat": {
+		Description: "Converts a number or a numeric string to a float.",
+		Examples:    []string{`{{.Ratio | toFloat}}`},
+	},
+	"toBool": {
+		Description: "Converts a bool, a number or a string such as \"true\" or \"0\" to a bool.",
+		Examples:    []string{`{{if .Enabled | toBool}}...{{end}}`},
+	},
+	"toStrings": {
+		Description: "Converts every element of a list to a string.",
+		Examples:    []string{`{{.Ports | toStrings | join ","}}`},
+	},
+	"kindOf": {
+		Description: "Returns the kind of a value e.g. string, int, slice, map or struct.",
+		Examples:    []string{`{{.Ports | kindOf}}`},
+	},
+	"typeOf": {
+		Description: "Returns the Go type of a value.",
+		Examples:    []string{`{{.Ports | typeOf}}`},
+	},
+	"kindIs": {
+		Description: "Reports whether a value is of the kind.",
+		Examples:    []string{`{{if .Ports | kindIs "slice"}}...{{end}}`},
+	},
+}
@@ -0,0 +1,117 @@
+package runtime_test
+
+import (
+	"errors"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Convert Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate toString method", func() {
+		const text = `[{{ .Int | toString }}|{{ .Float | toString }}|{{ .Nil | toString }}|{{ .Bytes | toString }}|{{ .Err | toString }}|{{ .Ptr | toString }}]`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		port := 8443
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"Int":   8080,
+			"Float": 1000000.0,
+			"Nil":   nil,
+			"Bytes": []byte("raw"),
+			"Err":   errors.New("oops"),
+			"Ptr":   &port,
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[8080\|1000000\|\|raw\|oops\|8443\]`))
+	})
+	Context("when validating toInt method", func() {
+		It("should convert numbers, strings and bools", func() {
+			const text = `{{ "8080" | toInt }} {{ 8080.0 | toInt }} {{ " 2.0 " | toInt }} {{ true | toInt }} {{ .Nil | toInt }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, map[string]interface{}{"Nil": nil})
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`8080 8080 2 1 0`))
+		})
+		It("should catch a fraction", func() {
+			const text = `{{ "2.5" | toInt }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+		})
+		It("should catch an invalid string", func() {
+			const text = `{{ "eighty" | toInt }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`cannot convert "eighty" to int`))
+		})
+	})
+	It("should validate toFloat method", func() {
+		const text = `{{ "2.5" | toFloat }} {{ 3 | toFloat }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`2.5 3`))
+	})
+	Context("when validating toBool method", func() {
+		It("should convert bools, numbers and strings", func() {
+			const text = `{{ "true" | toBool }} {{ "0" | toBool }} {{ 2 | toBool }} {{ 0.0 | toBool }} {{ .Nil | toBool }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, map[string]interface{}{"Nil": nil})
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`true false true false false`))
+		})
+		It("should catch an invalid value", func() {
+			const text = `{{ . | toBool }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, []string{})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`cannot convert []string to bool`))
+		})
+	})
+	It("should validate toStrings method", func() {
+		const text = `{{ range .List | toStrings }}[{{ . }}]{{ end }} {{ .One | toStrings | len }} {{ .Nil | toStrings | len }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"List": []interface{}{"a", 1, nil, 2.5},
+			"One":  "a",
+			"Nil":  nil,
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[a\]\[1\]\[\]\[2.5\] 1 0`))
+	})
+	It("should validate kindOf, typeOf and kindIs methods", func() {
+		const text = `{{ .List | kindOf }} {{ .List | typeOf }} {{ .List | kindIs "slice" }} {{ .Map | kindOf }} {{ .Nil | kindOf }} {{ .Nil | typeOf }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"List": []int{1},
+			"Map":  map[string]int{},
+			"Nil":  nil,
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`slice \[\]int true map invalid <nil>`))
+	})
+})
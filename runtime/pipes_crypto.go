@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"text/template"
+)
+
+//cryptoPipes are helper functions for checksums and derived credentials
+func cryptoPipes() template.FuncMap {
+	return template.FuncMap{
+		"md5": func(v interface{}) (string, error) {
+			sum := md5.Sum([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		"sha1": func(v interface{}) (string, error) {
+			sum := sha1.Sum([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		"sha256": func(v interface{}) (string, error) {
+			sum := sha256.Sum256([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		"sha512": func(v interface{}) (string, error) {
+			sum := sha512.Sum512([]byte(toString(v)))
+			return hex.EncodeToString(sum[:]), nil
+		},
+		//e.g. hmacSha256 .Private.Secret .Payload  => hex encoded signature
+		"hmacSha256": func(key string, v interface{}) (string, error) {
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write([]byte(toString(v)))
+			return hex.EncodeToString(mac.Sum(nil)), nil
+		},
+		"bcrypt": func(v interface{}) (string, error) {
+			return bcryptHash(toString(v))
+		},
+		//e.g. htpasswd "admin" .Private.Password  => "admin:$2a$10$..."
+		"htpasswd": func(user string, v interface{}) (string, error) {
+			hash, err := bcryptHash(toString(v))
+			if err != nil {
+				return "", err
+			}
+			return user + ":" + hash, nil
+		},
+		//e.g. derivePassword 16 "db-admin" .Private.Secret  => the same 16 characters for every render
+		"derivePassword": func(length int, label string, v interface{}) (string, error) {
+			return derivePassword(length, label, toString(v))
+		},
+	}
+}
+
+const (
+	derivePasswordChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	derivePasswordMax   = 1024
+)
+
+//derivePassword deterministically expands HMAC-SHA256(master, label) into an alphanumeric password
+func derivePassword(length int, label, master string) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("derivePassword: invalid length %d", length)
+	}
+	if length > derivePasswordMax {
+		return "", fmt.Errorf("derivePassword: length %d exceeds %d", length, derivePasswordMax)
+	}
+	out := make([]byte, 0, length)
+	counter := uint32(0)
+	for len(out) < length {
+		mac := hmac.New(sha256.New, []byte(master))
+		binary.Write(mac, binary.BigEndian, counter)
+		mac.Write([]byte(label))
+		for _, b := range mac.Sum(nil) {
+			//skip bytes that would bias the distribution towards the first characters
+			if int(b) >= 256-256%len(derivePasswordChars) {
+				continue
+			}
+			out = append(out, derivePasswordChars[int(b)%len(derivePasswordChars)])
+			if len(out) == length {
+				break
+			}
+		}
+		counter++
+	}
+	return string(out), nil
+}
+
+var cryptoPipesInfo = map[string]PipeInfo{
+	"md5": {
+		Description: "Returns the hex encoded MD5 checksum.",
+		Examples:    []string{`{{.Private.Cert | md5}}`},
+	},
+	"sha1": {
+		Description: "Returns the hex encoded SHA-1 checksum.",
+		Examples:    []string{`{{.Private.Cert | sha1}}`},
+	},
+	"sha256": {
+		Description: "Returns the hex encoded SHA-256 checksum.",
+		Examples:    []string{`{{.Private.Cert | sha256}}`},
+	},
+	"sha512": {
+		Description: "Returns the hex encoded SHA-512 checksum.",
+		Examples:    []string{`{{.Private.Cert | sha512}}`},
+	},
+	"hmacSha256": {
+		Description: "Returns the hex encoded HMAC-SHA256 signature with the key.",
+		Examples:    []string{`{{.Payload | hmacSha256 .Private.Secret}}`},
+	},
+	"bcrypt": {
+		Description: "Returns the bcrypt hash of the password with a random salt, it changes on every render.",
+		Examples:    []string{`{{.Private.Password | bcrypt}}`},
+	},
+	"htpasswd": {
+		Description: "Returns an htpasswd entry of the user with the bcrypt hash of the password.",
+		Examples:    []string{`{{.Private.Password | htpasswd "admin"}}`},
+	},
+	"derivePassword": {
+		Description: "Derives an alphanumeric password of the length (at most 1024) from a master secret and a label, the same inputs derive the same password.",
+		Examples:    []string{`{{.Private.Secret | derivePassword 16 "db-admin"}}`},
+	},
+}
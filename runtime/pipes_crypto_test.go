@@ -0,0 +1,118 @@
+package runtime_test
+
+import (
+	"strings"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Crypto Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate hex digest methods", func() {
+		const text = `{{ . | md5 }} {{ . | sha1 }} {{ . | sha256 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "abc")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`900150983cd24fb0d6963f7d28e17f72 ` +
+			`a9993e364706816aba3e25717850c26c9cd0d89d ` +
+			`ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad`))
+	})
+	It("should hash an int value", func() {
+		const text = `{{ .Port | sha256 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"Port": 8080})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`6c237681e70921603a306be9a1a5d9833fce5c1e268f52b1650970eaad0dce21`))
+	})
+	It("should validate sha512 method", func() {
+		const text = `{{ . | sha512 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "abc")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`^ddaf35a193617aba`))
+	})
+	It("should validate hmacSha256 method", func() {
+		const text = `{{ . | hmacSha256 "key" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "The quick brown fox jumps over the lazy dog")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8`))
+	})
+	Context("when validating bcrypt methods", func() {
+		It("should hash with a random salt", func() {
+			const text = `{{ . | bcrypt }} {{ . | bcrypt }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "secret")
+			Expect(err).To(BeNil())
+			out := strings.Split(string(buffer.Contents()), " ")
+			Expect(out[0]).To(MatchRegexp(`^\$2a\$10\$[./A-Za-z0-9]{53}$`))
+			Expect(out[0]).ToNot(Equal(out[1]))
+		})
+		It("should render an htpasswd entry", func() {
+			const text = `{{ . | htpasswd "admin" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "secret")
+			Expect(err).To(BeNil())
+			Expect(string(buffer.Contents())).To(MatchRegexp(`^admin:\$2a\$10\$[./A-Za-z0-9]{53}$`))
+		})
+		It("should catch a password longer than 72 bytes", func() {
+			const text = `{{ . | bcrypt }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, strings.Repeat("a", 73))
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("longer than 72 bytes"))
+		})
+	})
+	Context("when validating a derivePassword method", func() {
+		It("should derive the same password for the same master and label", func() {
+			const text = `{{ . | derivePassword 24 "db" }} {{ . | derivePassword 24 "db" }} {{ . | derivePassword 24 "web" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "master")
+			Expect(err).To(BeNil())
+			out := strings.Split(string(buffer.Contents()), " ")
+			Expect(out[0]).To(HaveLen(24))
+			Expect(out[0]).To(MatchRegexp(`^[a-zA-Z0-9]+$`))
+			Expect(out[0]).To(Equal(out[1]))
+			Expect(out[0]).ToNot(Equal(out[2]))
+		})
+		It("should catch an invalid length", func() {
+			const text = `{{ . | derivePassword 0 "db" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "master")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("invalid length"))
+		})
+		It("should catch a length above the cap", func() {
+			const text = `{{ . | derivePassword 1000000000 "db" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "master")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("length 1000000000 exceeds 1024"))
+		})
+	})
+})
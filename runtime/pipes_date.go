@@ -0,0 +1,170 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+//SetNow pins the clock used by the date pipes so that renders are reproducible
+func (p *Pipes) SetNow(t time.Time) {
+	p.clock = t
+}
+
+func (p *Pipes) now() time.Time {
+	if p.clock.IsZero() {
+		return time.Now()
+	}
+	return p.clock
+}
+
+//datePipes are helper functions for timestamps and durations
+func (p *Pipes) datePipes() template.FuncMap {
+	return template.FuncMap{
+		"now": func() (time.Time, error) {
+			return p.now(), nil
+		},
+		//e.g. date "2006-01-02" now  => "2016-03-20"
+		"date": func(layout string, v interface{}) (string, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return "", err
+			}
+			return t.Format(layout), nil
+		},
+		//e.g. dateModify "+24h" now  => tomorrow at the same time
+		"dateModify": func(d string, v interface{}) (time.Time, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return time.Time{}, err
+			}
+			duration, err := time.ParseDuration(d)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return t.Add(duration), nil
+		},
+		"toUnix": func(v interface{}) (int64, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return 0, err
+			}
+			return t.Unix(), nil
+		},
+		//e.g. duration 95  => 1m35s, duration "1h30m"  => 1h30m0s
+		"duration": func(v interface{}) (time.Duration, error) {
+			return toDuration(v)
+		},
+		//e.g. durationRound "49h10m"  => "2d"; a time is rounded as the duration since now
+		"durationRound": func(v interface{}) (string, error) {
+			var d time.Duration
+			if t, ok := v.(time.Time); ok {
+				d = p.now().Sub(t)
+			} else {
+				var err error
+				if d, err = toDuration(v); err != nil {
+					return "", err
+				}
+			}
+			return roundDuration(d), nil
+		},
+	}
+}
+
+//toTime accepts a time.Time, unix seconds or a RFC3339 string, unix seconds are in UTC
+//so that a render does not depend on the local time zone
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		return *t, nil
+	case int:
+		return time.Unix(int64(t), 0).UTC(), nil
+	case int64:
+		return time.Unix(t, 0).UTC(), nil
+	case float64:
+		//numbers decoded from JSON are float64
+		sec := math.Floor(t)
+		return time.Unix(int64(sec), int64((t-sec)*float64(time.Second))).UTC(), nil
+	case string:
+		return time.Parse(time.RFC3339, t)
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to time", v)
+}
+
+//toDuration accepts a time.Duration, seconds or a duration string e.g. "1h30m"
+func toDuration(v interface{}) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case int:
+		return time.Duration(d) * time.Second, nil
+	case int64:
+		return time.Duration(d) * time.Second, nil
+	case float64:
+		return time.Duration(d * float64(time.Second)), nil
+	case string:
+		if s, err := strconv.ParseInt(d, 10, 64); err == nil {
+			return time.Duration(s) * time.Second, nil
+		}
+		return time.ParseDuration(d)
+	}
+	return 0, fmt.Errorf("cannot convert %T to duration", v)
+}
+
+//roundDuration keeps only the most significant unit of a duration
+func roundDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	const day = 24 * time.Hour
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{365 * day, "y"},
+		{30 * day, "mo"},
+		{day, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, u := range units {
+		if d >= u.size {
+			return fmt.Sprintf("%s%d%s", sign, d/u.size, u.name)
+		}
+	}
+	return "0s"
+}
+
+var datePipesInfo = map[string]PipeInfo{
+	"now": {
+		Description: "Returns the current time or the pinned clock.",
+		Examples:    []string{`{{now | date "2006-01-02"}}`},
+	},
+	"date": {
+		Description: "Formats a time, unix seconds in UTC or a RFC3339 string with a Go layout.",
+		Examples:    []string{`{{.Created | date "Jan 2 2006"}}`},
+	},
+	"dateModify": {
+		Description: "Adds a duration to a time.",
+		Examples:    []string{`{{now | dateModify "+24h"}}`},
+	},
+	"toUnix": {
+		Description: "Returns the unix seconds of a time.",
+		Examples:    []string{`{{now | toUnix}}`},
+	},
+	"duration": {
+		Description: "Converts seconds or a duration string to a duration.",
+		Examples:    []string{`{{95 | duration}}`, `{{"1h30m" | duration}}`},
+	},
+	"durationRound": {
+		Description: "Rounds a duration, or the time since a time, to its most significant unit.",
+		Examples:    []string{`{{"49h10m" | durationRound}}`},
+	},
+}
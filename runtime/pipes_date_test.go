@@ -0,0 +1,115 @@
+package runtime_test
+
+import (
+	"text/template"
+	"time"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Date Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		pipes.SetNow(time.Date(2016, 3, 20, 15, 4, 5, 0, time.UTC))
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should use the pinned clock for now method", func() {
+		const text = `{{ now | date "2006-01-02T15:04:05" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`2016-03-20T15:04:05`))
+	})
+	It("should use the current time without a pinned clock", func() {
+		tmpl = template.New("tester").Funcs(NewPipes().Map)
+		const text = `{{ now | date "2006" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`%s`, time.Now().Format("2006")))
+	})
+	It("should validate date method with a RFC3339 string and unix seconds", func() {
+		const text = `{{ .A | date "Jan 2 2006" }}, {{ .B | date "2006" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"A": "2015-12-25T10:00:00Z", "B": 0})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`Dec 25 2015, 1970`))
+	})
+	Context("when the local time zone is not UTC", func() {
+		var local *time.Location
+		BeforeEach(func() {
+			local = time.Local
+			time.Local = time.FixedZone("JST", 9*60*60)
+		})
+		AfterEach(func() {
+			time.Local = local
+		})
+
+		It("should render unix seconds in UTC", func() {
+			const text = `{{ .A | date "2006-01-02 15:04 MST" }}, {{ .B | date "2006-01-02 15:04:05.000 MST" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, map[string]interface{}{"A": int64(1458486245), "B": 1458486245.5})
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`2016-03-20 15:04 UTC, 2016-03-20 15:04:05.500 UTC`))
+		})
+	})
+	It("should validate dateModify method", func() {
+		const text = `{{ now | dateModify "+24h" | date "2006-01-02" }} {{ now | dateModify "-1h30m" | date "15:04" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`2016-03-21 13:34`))
+	})
+	It("should validate toUnix method", func() {
+		const text = `{{ now | toUnix }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`1458486245`))
+	})
+	It("should validate duration method", func() {
+		const text = `{{ .A | duration }} {{ .B | duration }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"A": 95, "B": "1h30m"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`1m35s 1h30m0s`))
+	})
+	It("should validate durationRound method", func() {
+		const text = `{{ .A | durationRound }} {{ .B | durationRound }} {{ .C | durationRound }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"A": "49h10m",
+			"B": 45,
+			"C": time.Date(2016, 3, 20, 12, 0, 0, 0, time.UTC),
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`2d 45s 3h`))
+	})
+	It("should catch an invalid time", func() {
+		const text = `{{ . | date "2006" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, true)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("cannot convert bool to time"))
+	})
+})
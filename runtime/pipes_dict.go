@@ -0,0 +1,273 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"text/template"
+)
+
+//dictPipes are helper functions for building and reading dicts, every pipe accepts
+//a map with string keys or a struct and returns a new map[string]interface{}
+func dictPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. template "job" (dict "Repo" . "Private" $global.Private)
+		"dict": func(a ...interface{}) (map[string]interface{}, error) {
+			if len(a)%2 != 0 {
+				return nil, fmt.Errorf("dict: odd number of args: %d", len(a))
+			}
+			out := make(map[string]interface{}, len(a)/2)
+			for i := 0; i < len(a); i += 2 {
+				key, ok := a[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("dict: key %v is %T, not a string", a[i], a[i])
+				}
+				out[key] = a[i+1]
+			}
+			return out, nil
+		},
+		"list": func(a ...interface{}) ([]interface{}, error) {
+			return append([]interface{}{}, a...), nil
+		},
+		//e.g. set "Port" 8080 .Config
+		"set": func(key string, value, d interface{}) (map[string]interface{}, error) {
+			out, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = value
+			return out, nil
+		},
+		"unset": func(key string, d interface{}) (map[string]interface{}, error) {
+			out, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			delete(out, key)
+			return out, nil
+		},
+		"keys": func(d interface{}) ([]string, error) {
+			m, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			return sortedKeys(m), nil
+		},
+		//values are ordered by their sorted keys
+		"values": func(d interface{}) ([]interface{}, error) {
+			m, err := toDict(d)
+			if err != nil {
+				return nil, err
+			}
+			out := []interface{}{}
+			for _, k := range sortedKeys(m) {
+				out = append(out, m[k])
+			}
+			return out, nil
+		},
+		"hasKey": func(key string, d interface{}) (bool, error) {
+			m, err := toDict(d)
+			if err != nil {
+				return false, err
+			}
+			_, ok := m[key]
+			return ok, nil
+		},
+		//e.g. get "Port" .Config or get "Port" 8080 .Config  => 8080 when "Port" is missing
+		"get": func(key string, a ...interface{}) (interface{}, error) {
+			if len(a) == 0 || len(a) > 2 {
+				return nil, fmt.Errorf("get: wrong number of args: got %d want 2 or 3", len(a)+1)
+			}
+			m, err := toDict(a[len(a)-1])
+			if err != nil {
+				return nil, err
+			}
+			if v, ok := m[key]; ok {
+				return v, nil
+			}
+			if len(a) == 2 {
+				return a[0], nil
+			}
+			return nil, nil
+		},
+		//e.g. merge .Defaults .Overrides  => the later dicts override the earlier keys
+		"merge": func(dicts ...interface{}) (map[string]interface{}, error) {
+			return mergeDicts(dicts, false)
+		},
+		//deepMerge also merges the nested dicts
+		"deepMerge": func(dicts ...interface{}) (map[string]interface{}, error) {
+			return mergeDicts(dicts, true)
+		},
+		//e.g. pick "Password" "Secret" .Private
+		"pick": func(a ...interface{}) (map[string]interface{}, error) {
+			m, keys, err := dictAndKeys("pick", a)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[string]interface{})
+			for _, k := range keys {
+				if v, ok := m[k]; ok {
+					out[k] = v
+				}
+			}
+			return out, nil
+		},
+		"omit": func(a ...interface{}) (map[string]interface{}, error) {
+			m, keys, err := dictAndKeys("omit", a)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				delete(m, k)
+			}
+			return m, nil
+		},
+	}
+}
+
+//toDict copies a map with string keys or the exported fields of a struct into a new map
+func toDict(d interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(d)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return map[string]interface{}{}, nil
+		}
+		rv = rv.Elem()
+	}
+	out := make(map[string]interface{})
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot use %T as a dict, keys are not strings", d)
+		}
+		for _, k := range rv.MapKeys() {
+			out[k.String()] = rv.MapIndex(k).Interface()
+		}
+		return out, nil
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			out[t.Field(i).Name] = rv.Field(i).Interface()
+		}
+		return out, nil
+	case reflect.Invalid:
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot use %T as a dict", d)
+}
+
+func isDict(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Struct || (rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergeDicts(dicts []interface{}, deep bool) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	for _, d := range dicts {
+		m, err := toDict(d)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range m {
+			if deep && isDict(v) && isDict(out[k]) {
+				merged, err := mergeDicts([]interface{}{out[k], v}, true)
+				if err != nil {
+					return nil, err
+				}
+				v = merged
+			}
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+//dictAndKeys splits the args of pick and omit into the piped dict and its string keys
+func dictAndKeys(name string, a []interface{}) (map[string]interface{}, []string, error) {
+	if len(a) == 0 {
+		return nil, nil, errors.New(name + ": missing dict")
+	}
+	m, err := toDict(a[len(a)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, len(a)-1)
+	for k, v := range a[:len(a)-1] {
+		s, ok := v.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: key %v is %T, not a string", name, v, v)
+		}
+		keys[k] = s
+	}
+	return m, keys, nil
+}
+
+var dictPipesInfo = map[string]PipeInfo{
+	"dict": {
+		Description: "Builds a dict from key and value pairs.",
+		Examples:    []string{`{{template "job" (dict "Repo" . "Private" $global.Private)}}`},
+	},
+	"list": {
+		Description: "Builds a list from the values.",
+		Examples:    []string{`{{range list "repo1" "repo2"}}...{{end}}`},
+	},
+	"set": {
+		Description: "Returns a dict with the key set to the value.",
+		Examples:    []string{`{{.Config | set "Port" 8080}}`},
+	},
+	"unset": {
+		Description: "Returns a dict without the key.",
+		Examples:    []string{`{{.Config | unset "Debug"}}`},
+	},
+	"keys": {
+		Description: "Returns the sorted keys of a dict.",
+		Examples:    []string{`{{.Config | keys}}`},
+	},
+	"values": {
+		Description: "Returns the values of a dict ordered by their sorted keys.",
+		Examples:    []string{`{{.Config | values}}`},
+	},
+	"hasKey": {
+		Description: "Reports whether a dict has the key.",
+		Examples:    []string{`{{if .Config | hasKey "Port"}}...{{end}}`},
+	},
+	"get": {
+		Description: "Returns the value of the key or the optional default.",
+		Examples:    []string{`{{.Config | get "Port" 8080}}`},
+	},
+	"merge": {
+		Description: "Merges dicts, the later dicts override the earlier keys.",
+		Examples:    []string{`{{.Overrides | merge .Defaults}}`},
+	},
+	"deepMerge": {
+		Description: "Merges dicts and their nested dicts, the later dicts override the earlier keys.",
+		Examples:    []string{`{{.Overrides | deepMerge .Defaults}}`},
+	},
+	"pick": {
+		Description: "Returns a dict with only the keys.",
+		Examples:    []string{`{{.Private | pick "Password" "Secret"}}`},
+	},
+	"omit": {
+		Description: "Returns a dict without the keys.",
+		Examples:    []string{`{{.Private | omit "Password"}}`},
+	},
+}
@@ -0,0 +1,119 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Dict Pipes", func() {
+	type Private struct {
+		Password, Secret string
+		Port             int
+		hidden           string
+	}
+	var (
+		pipes   *Pipes
+		tmpl    *template.Template
+		buffer  *gbytes.Buffer
+		private = Private{Password: "team3", Secret: "cloud-foundry", Port: 22, hidden: "x"}
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	Context("when validating a dict method", func() {
+		It("should pass several values to a nested template", func() {
+			const text = `{{ define "job" }}{{.Name}}:{{.Private.Secret}}{{ end }}{{ template "job" (dict "Name" "repo1" "Private" .) }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, private)
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`repo1:cloud-foundry`))
+		})
+		It("should catch an odd number of args", func() {
+			const text = `{{ dict "Name" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("odd number of args"))
+		})
+	})
+	It("should validate list method", func() {
+		const text = `{{ range list "a" 1 true }}{{.}} {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`a 1 true `))
+	})
+	It("should validate set and unset methods without changing the input", func() {
+		const text = `{{ $d := . | set "Port" 2222 | unset "Secret" }}{{ $d | keys | join "," }} {{ $d.Port }} {{ .Port }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, private)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`Password,Port 2222 22`))
+	})
+	It("should validate keys and values methods on structs and maps", func() {
+		const text = `{{ . | keys | join "," }} {{ . | values }} {{ .Private | keys | join "," }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"b": 2, "a": 1, "Private": private})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`Private,a,b \[{team3 cloud-foundry 22 x} 1 2\] Password,Port,Secret`))
+	})
+	It("should validate hasKey and get methods", func() {
+		const text = `{{ . | hasKey "Port" }} {{ . | hasKey "User" }} {{ . | get "Port" }} {{ . | get "User" "root" }} {{ . | get "Port" 80 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, private)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true false 22 root 22`))
+	})
+	It("should validate merge method with the later dicts overriding", func() {
+		const text = `{{ $d := .Overrides | merge .Defaults }}{{ $d.Port }} {{ $d.User }} {{ $d.TLS.Cert }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"Defaults":  map[string]interface{}{"Port": 80, "User": "root", "TLS": map[string]string{"Key": "k", "Cert": "c"}},
+			"Overrides": map[string]interface{}{"Port": 443, "TLS": map[string]string{"Key": "k2"}},
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`443 root <no value>`))
+	})
+	It("should validate deepMerge method", func() {
+		const text = `{{ $d := .Overrides | deepMerge .Defaults }}{{ $d.Port }} {{ $d.TLS.Key }} {{ $d.TLS.Cert }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"Defaults":  map[string]interface{}{"Port": 80, "TLS": map[string]string{"Key": "k", "Cert": "c"}},
+			"Overrides": map[string]interface{}{"Port": 443, "TLS": map[string]string{"Key": "k2"}},
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`443 k2 c`))
+	})
+	It("should validate pick and omit methods", func() {
+		const text = `{{ . | pick "Password" "Port" | keys | join "," }} {{ . | omit "Password" "Port" | keys | join "," }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, private)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`Password,Port Secret`))
+	})
+	It("should catch a value that is not a dict", func() {
+		const text = `{{ . | keys }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, []string{"a"})
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("cannot use []string as a dict"))
+	})
+})
@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/template"
+)
+
+//AllowEnv sets the patterns of the environment variables the env pipes can read e.g. "CI_*",
+//every variable is refused without a pattern
+func (p *Pipes) AllowEnv(patterns ...string) {
+	p.envAllow = patterns
+}
+
+//envPipes are helper functions for reading the allowed environment variables
+func (p *Pipes) envPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. env "CI_BRANCH"  => "" when the variable is not set
+		"env": func(name string) (string, error) {
+			if err := p.allowedEnv("env", name); err != nil {
+				return "", err
+			}
+			return os.Getenv(name), nil
+		},
+		"requiredEnv": func(name string) (string, error) {
+			if err := p.allowedEnv("requiredEnv", name); err != nil {
+				return "", err
+			}
+			v := os.Getenv(name)
+			if v == "" {
+				return "", fmt.Errorf("requiredEnv: %s is not set", name)
+			}
+			return v, nil
+		},
+		//e.g. expandEnv "https://${CI_HOST}/$CI_REPO"
+		"expandEnv": func(s string) (string, error) {
+			var err error
+			out := os.Expand(s, func(name string) string {
+				if e := p.allowedEnv("expandEnv", name); e != nil {
+					if err == nil {
+						err = e
+					}
+					return ""
+				}
+				return os.Getenv(name)
+			})
+			return out, err
+		},
+	}
+}
+
+func (p *Pipes) allowedEnv(pipe, name string) error {
+	for _, v := range p.envAllow {
+		if ok, _ := path.Match(v, name); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %s is not allowed, see --allow-env", pipe, name)
+}
+
+var envPipesInfo = map[string]PipeInfo{
+	"env": {
+		Description: "Returns an allowed environment variable, empty when it is not set.",
+		Examples:    []string{`{{env "CI_BRANCH" | default "master"}}`},
+	},
+	"requiredEnv": {
+		Description: "Returns an allowed environment variable or fails when it is not set or empty.",
+		Examples:    []string{`{{requiredEnv "DEPLOY_TOKEN"}}`},
+	},
+	"expandEnv": {
+		Description: "Replaces $VAR and ${VAR} of allowed environment variables in a string.",
+		Examples:    []string{`{{.Repo | expandEnv}}`},
+	},
+}
@@ -0,0 +1,81 @@
+package runtime_test
+
+import (
+	"os"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Env Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+		os.Setenv("GOFLAT_TEST_HOST", "ci.example.com")
+		os.Setenv("GOFLAT_TEST_REPO", "repo1")
+		os.Setenv("GOFLAT_SECRET", "SECRET")
+		pipes.AllowEnv("GOFLAT_TEST_*")
+	})
+	AfterEach(func() {
+		os.Unsetenv("GOFLAT_TEST_HOST")
+		os.Unsetenv("GOFLAT_TEST_REPO")
+		os.Unsetenv("GOFLAT_SECRET")
+	})
+
+	It("should validate env method", func() {
+		const text = `{{ env "GOFLAT_TEST_HOST" }}|{{ env "GOFLAT_TEST_MISSING" | default "none" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`ci.example.com\|none`))
+	})
+	It("should validate requiredEnv method", func() {
+		tmpl, err := tmpl.Parse(`{{ requiredEnv "GOFLAT_TEST_MISSING" }}`)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring(`GOFLAT_TEST_MISSING is not set`))
+	})
+	It("should validate expandEnv method", func() {
+		const text = `{{ . | expandEnv }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "https://${GOFLAT_TEST_HOST}/$GOFLAT_TEST_REPO")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`https://ci.example.com/repo1`))
+	})
+	Context("when reading a variable that is not allowed", func() {
+		It("should catch env", func() {
+			tmpl, err := tmpl.Parse(`{{ env "GOFLAT_SECRET" }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`GOFLAT_SECRET is not allowed`))
+		})
+		It("should catch expandEnv", func() {
+			tmpl, err := tmpl.Parse(`{{ . | expandEnv }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "$GOFLAT_TEST_HOST $GOFLAT_SECRET")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`GOFLAT_SECRET is not allowed`))
+		})
+		It("should refuse every variable by default", func() {
+			pipes.AllowEnv()
+			tmpl, err := tmpl.Parse(`{{ env "GOFLAT_TEST_HOST" }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//SetFileRoot sets the directory the file pipes resolve the relative paths from, usually the directory
+//of the template, and the root directory they cannot read outside of
+func (p *Pipes) SetFileRoot(dir, root string) {
+	p.fileDir, p.fileRoot = dir, root
+}
+
+//filePipes are helper functions for reading the files next to the template
+func (p *Pipes) filePipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. readFile "certs/ca.pem"
+		"readFile": func(name string) (string, error) {
+			file, err := p.resolve("readFile", name)
+			if err != nil {
+				return "", err
+			}
+			data, err := ioutil.ReadFile(file)
+			return string(data), err
+		},
+		//e.g. readLines "hosts.txt"  => a line without its line ending for every line
+		"readLines": func(name string) ([]string, error) {
+			file, err := p.resolve("readLines", name)
+			if err != nil {
+				return nil, err
+			}
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) == 0 {
+				return []string{}, nil
+			}
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			for k, v := range lines {
+				lines[k] = strings.TrimSuffix(v, "\r")
+			}
+			return lines, nil
+		},
+		//e.g. fileGlob "scripts/*.sh"  => the sorted matches relative to the template directory, without the
+		//matches linking outside of the root directory
+		"fileGlob": func(pattern string) ([]string, error) {
+			dir, root, err := p.fileDirs()
+			if err != nil {
+				return nil, err
+			}
+			abs := pattern
+			if !filepath.IsAbs(abs) {
+				abs = filepath.Join(dir, abs)
+			}
+			if !insideDir(root, filepath.Clean(abs)) {
+				return nil, fmt.Errorf("fileGlob: %q is outside of the root directory %q", pattern, root)
+			}
+			matches, err := filepath.Glob(abs)
+			if err != nil {
+				return nil, err
+			}
+			out := []string{}
+			for _, v := range matches {
+				//the matches linking outside of the root directory are dropped
+				if resolved, err := filepath.EvalSymlinks(v); err != nil || !insideDir(root, resolved) {
+					continue
+				}
+				if filepath.IsAbs(pattern) {
+					out = append(out, v)
+				} else if rel, err := filepath.Rel(dir, v); err == nil {
+					out = append(out, filepath.ToSlash(rel))
+				}
+			}
+			sort.Strings(out)
+			return out, nil
+		},
+	}
+}
+
+//fileDirs returns the absolute directory of the relative paths and the root directory without symlinks,
+//both are the working directory by default
+func (p *Pipes) fileDirs() (string, string, error) {
+	dir, root := p.fileDir, p.fileRoot
+	if dir == "" {
+		dir = "."
+	}
+	if root == "" {
+		root = dir
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return "", "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", "", err
+	}
+	return dir, root, nil
+}
+
+//resolve returns the file of a path relative to the template directory, following symlinks,
+//when it is inside of the root directory
+func (p *Pipes) resolve(pipe, name string) (string, error) {
+	dir, root, err := p.fileDirs()
+	if err != nil {
+		return "", err
+	}
+	file := name
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	if !insideDir(root, filepath.Clean(file)) {
+		return "", fmt.Errorf("%s: %q is outside of the root directory %q", pipe, name, root)
+	}
+	resolved, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return "", err
+	}
+	if !insideDir(root, resolved) {
+		return "", fmt.Errorf("%s: %q links outside of the root directory %q", pipe, name, root)
+	}
+	if fi, err := os.Stat(resolved); err == nil && fi.IsDir() {
+		return "", fmt.Errorf("%s: %q is a directory", pipe, name)
+	}
+	return resolved, nil
+}
+
+func insideDir(dir, file string) bool {
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+var filePipesInfo = map[string]PipeInfo{
+	"readFile": {
+		Description: "Reads a file relative to the template directory within the root directory.",
+		Examples:    []string{`{{readFile "certs/ca.pem" | indent 6}}`},
+	},
+	"readLines": {
+		Description: "Reads the lines of a file relative to the template directory within the root directory.",
+		Examples:    []string{`{{range readLines "hosts.txt"}}...{{end}}`},
+	},
+	"fileGlob": {
+		Description: "Returns the sorted files matching a pattern relative to the template directory within the root directory.",
+		Examples:    []string{`{{range fileGlob "scripts/*.sh"}}{{readFile .}}{{end}}`},
+	},
+}
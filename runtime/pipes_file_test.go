@@ -0,0 +1,121 @@
+package runtime_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("File Pipes", func() {
+	var (
+		pipes   *Pipes
+		tmpl    *template.Template
+		buffer  *gbytes.Buffer
+		rootDir string
+		tmplDir string
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+
+		rootDir, _ = ioutil.TempDir(os.TempDir(), "")
+		tmplDir = filepath.Join(rootDir, "templates")
+		files := map[string]string{
+			filepath.Join(rootDir, "secret.txt"):            "SECRET",
+			filepath.Join(tmplDir, "certs", "ca.pem"):       "-----BEGIN CERTIFICATE-----\nMIIB\n",
+			filepath.Join(tmplDir, "hosts.txt"):             "10.0.0.1\r\n10.0.0.2\n",
+			filepath.Join(tmplDir, "scripts", "b.sh"):       "echo b",
+			filepath.Join(tmplDir, "scripts", "a.sh"):       "echo a",
+			filepath.Join(tmplDir, "scripts", "README.txt"): "",
+		}
+		for k, v := range files {
+			Expect(os.MkdirAll(filepath.Dir(k), 0777)).To(Succeed())
+			Expect(ioutil.WriteFile(k, []byte(v), 0666)).To(Succeed())
+		}
+		Expect(os.Symlink(filepath.Join(rootDir, "secret.txt"), filepath.Join(tmplDir, "link.txt"))).To(Succeed())
+		pipes.SetFileRoot(tmplDir, "")
+	})
+	AfterEach(func() {
+		os.RemoveAll(rootDir)
+	})
+
+	It("should validate readFile method", func() {
+		const text = `{{ readFile "certs/ca.pem" | indent 2 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`  -----BEGIN CERTIFICATE-----\n  MIIB\n`))
+	})
+	It("should validate readLines method", func() {
+		const text = `{{ range readLines "hosts.txt" }}[{{ . }}]{{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`^\[10.0.0.1\]\[10.0.0.2\]$`))
+	})
+	It("should validate fileGlob method", func() {
+		const text = `{{ range fileGlob "scripts/*.sh" }}{{ . }}:{{ readFile . }} {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`^scripts/a.sh:echo a scripts/b.sh:echo b $`))
+	})
+	It("should drop the fileGlob matches linking outside of the root directory", func() {
+		Expect(os.Symlink(filepath.Join(tmplDir, "hosts.txt"), filepath.Join(tmplDir, "hosts-link.txt"))).To(Succeed())
+		const text = `{{ fileGlob "*.txt" | join "," }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`^hosts-link.txt,hosts.txt$`))
+	})
+	Context("when reading outside of the root directory", func() {
+		It("should catch a relative path", func() {
+			tmpl, err := tmpl.Parse(`{{ readFile "../secret.txt" }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`"../secret.txt" is outside of the root directory`))
+		})
+		It("should catch an absolute path", func() {
+			tmpl, err := tmpl.Parse(`{{ readLines "/etc/passwd" }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`outside of the root directory`))
+		})
+		It("should catch a symlink", func() {
+			tmpl, err := tmpl.Parse(`{{ readFile "link.txt" }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`"link.txt" links outside of the root directory`))
+		})
+		It("should catch a glob", func() {
+			tmpl, err := tmpl.Parse(`{{ fileGlob "../*.txt" }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, nil)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`outside of the root directory`))
+		})
+	})
+	It("should read inside of a configured root directory", func() {
+		pipes.SetFileRoot(tmplDir, rootDir)
+		tmpl, err := tmpl.Parse(`{{ readFile "../secret.txt" }} {{ readFile "link.txt" }}`)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`SECRET SECRET`))
+	})
+})
@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+//HTMLTemplate converts the parsed templates of t into a html/template set that escapes the values
+//according to their HTML, attribute, JS or URL context, the options are set as with template.Option.
+//From then on include and tpl render with html/template
+func (p *Pipes) HTMLTemplate(t *template.Template, options ...string) (*htmltemplate.Template, error) {
+	h, err := p.htmlTemplate(t, options)
+	if err != nil {
+		return nil, err
+	}
+	p.html, p.htmlOptions = h, options
+	return h, nil
+}
+
+func (p *Pipes) htmlTemplate(t *template.Template, options []string) (*htmltemplate.Template, error) {
+	h := htmltemplate.New(t.Name()).Funcs(htmltemplate.FuncMap(p.Map)).Option(options...)
+	for _, v := range t.Templates() {
+		if v.Tree == nil {
+			continue
+		}
+		if _, err := h.AddParseTree(v.Name(), v.Tree.Copy()); err != nil {
+			return nil, err
+		}
+	}
+	//AddParseTree adds the root as a new template of the set
+	if root := h.Lookup(t.Name()); root != nil {
+		return root, nil
+	}
+	return h, nil
+}
+
+//keepHTML returns s as HTML when v is HTML, so that e.g. the output of include stays escaped once
+//when it is indented
+func keepHTML(v interface{}, s string) interface{} {
+	if _, ok := v.(htmltemplate.HTML); ok {
+		return htmltemplate.HTML(s)
+	}
+	return s
+}
+
+//htmlPipes are helper functions for marking trusted values that html/template does not escape,
+//they have no effect with text/template
+func htmlPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. safeHTML "<b>bold</b>"
+		"safeHTML": func(s string) (htmltemplate.HTML, error) {
+			return htmltemplate.HTML(s), nil
+		},
+		//e.g. <div {{.Attrs | safeAttr}}>
+		"safeAttr": func(s string) (htmltemplate.HTMLAttr, error) {
+			return htmltemplate.HTMLAttr(s), nil
+		},
+		"safeJS": func(s string) (htmltemplate.JS, error) {
+			return htmltemplate.JS(s), nil
+		},
+		"safeURL": func(s string) (htmltemplate.URL, error) {
+			return htmltemplate.URL(s), nil
+		},
+		"safeCSS": func(s string) (htmltemplate.CSS, error) {
+			return htmltemplate.CSS(s), nil
+		},
+	}
+}
+
+var htmlPipesInfo = map[string]PipeInfo{
+	"safeHTML": {
+		Description: "Marks a trusted string as HTML that --engine html does not escape.",
+		Examples:    []string{`{{.Description | safeHTML}}`},
+	},
+	"safeAttr": {
+		Description: "Marks a trusted string as HTML attributes that --engine html does not escape.",
+		Examples:    []string{`<div {{.Attrs | safeAttr}}>`},
+	},
+	"safeJS": {
+		Description: "Marks a trusted string as a JavaScript expression that --engine html does not escape.",
+		Examples:    []string{`<script>var config = {{.Config | safeJS}};</script>`},
+	},
+	"safeURL": {
+		Description: "Marks a trusted string as a URL that --engine html does not filter or escape.",
+		Examples:    []string{`<a href="{{.Link | safeURL}}">`},
+	},
+	"safeCSS": {
+		Description: "Marks a trusted string as CSS that --engine html does not escape.",
+		Examples:    []string{`<p style="{{.Style | safeCSS}}">`},
+	},
+}
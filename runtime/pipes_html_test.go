@@ -0,0 +1,70 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("HTML Pipes", func() {
+	var (
+		pipes  *Pipes
+		buffer *gbytes.Buffer
+		data   map[string]string
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		buffer = gbytes.NewBuffer()
+		data = map[string]string{
+			"Name": `<b>"jane"</b>`,
+			"Link": "javascript:alert(1)",
+		}
+	})
+	execute := func(text string, options ...string) error {
+		tmpl, err := template.New("tester").Funcs(pipes.Map).Parse(text)
+		Expect(err).To(BeNil())
+		pipes.SetTemplate(tmpl)
+		h, err := pipes.HTMLTemplate(tmpl, options...)
+		Expect(err).To(BeNil())
+		return h.Execute(buffer, data)
+	}
+
+	Context("when validating HTMLTemplate method", func() {
+		It("should escape the values according to their context", func() {
+			err := execute(`<p title="{{.Name}}">{{.Name | toUpper}}</p><a href="{{.Link}}">x</a><script>var n = {{.Name}};</script>`)
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`<p title="&lt;b&gt;&#34;jane&#34;&lt;/b&gt;">&lt;B&gt;&#34;JANE&#34;&lt;/B&gt;</p>`))
+			Eventually(buffer).Should(gbytes.Say(`<a href="#ZgotmplZ">x</a>`))
+			Eventually(buffer).Should(gbytes.Say(`var n = "\\u003cb\\u003e`))
+		})
+		It("should render the named templates with include and tpl once escaped", func() {
+			err := execute(`{{define "name"}}<i>{{.Name}}</i>{{end}}<p>{{include "name" .}}</p><p>{{tpl "<u>{{.Name}}</u>" .}}</p>`)
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`<p><i>&lt;b&gt;&#34;jane&#34;&lt;/b&gt;</i></p><p><u>&lt;b&gt;&#34;jane&#34;&lt;/b&gt;</u></p>`))
+		})
+		It("should indent and trim the output of include", func() {
+			err := execute(`{{define "job"}}
+<i>{{.Name}}</i>
+<b>x</b>{{end}}<pre>
+{{include "job" . | trim | indent 2}}</pre>`)
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`<pre>\n  <i>&lt;b&gt;&#34;jane&#34;&lt;/b&gt;</i>\n  <b>x</b></pre>`))
+		})
+		It("should set the options", func() {
+			err := execute(`{{.Missing}}`, "missingkey=error")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`map has no entry for key "Missing"`))
+		})
+	})
+	Context("when validating safe methods", func() {
+		It("should not escape the trusted values", func() {
+			err := execute(`{{.Name | safeHTML}}<a href="{{.Link | safeURL}}" {{"data-x=1" | safeAttr}}>`)
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`<b>"jane"</b><a href="javascript:alert%281%29" data-x=1>`))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+)
+
+//maxIncludeDepth limits the nested include and tpl calls so that a recursive template fails instead of looping
+const maxIncludeDepth = 32
+
+//SetTemplate sets the template whose named templates are rendered by include and tpl
+func (p *Pipes) SetTemplate(t *template.Template) {
+	p.tmpl = t
+}
+
+//includePipes are helper functions for rendering templates into a string that can be piped,
+//with html/template the result is HTML that is not escaped again
+func (p *Pipes) includePipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. include "job" . | indent 2
+		"include": func(name string, data interface{}) (interface{}, error) {
+			if p.tmpl == nil || p.tmpl.Lookup(name) == nil {
+				return "", fmt.Errorf("include: template %q is not defined", name)
+			}
+			if p.html != nil {
+				return p.renderHTML("include", name, p.html, data)
+			}
+			return p.render("include", name, p.tmpl, data)
+		},
+		//e.g. tpl .Config.Message .  => renders the field as a template with the named templates
+		"tpl": func(text string, data interface{}) (interface{}, error) {
+			t := template.New("tpl").Funcs(p.Map)
+			if p.tmpl != nil {
+				var err error
+				if t, err = p.tmpl.Clone(); err != nil {
+					return "", err
+				}
+				t = t.New("tpl")
+			}
+			if _, err := t.Parse(text); err != nil {
+				return "", err
+			}
+			if p.html != nil {
+				h, err := p.htmlTemplate(t, p.htmlOptions)
+				if err != nil {
+					return "", err
+				}
+				return p.renderHTML("tpl", "tpl", h, data)
+			}
+			return p.render("tpl", "tpl", t, data)
+		},
+	}
+}
+
+//executor is a text/template or a html/template set
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+func (p *Pipes) render(pipe, name string, t executor, data interface{}) (string, error) {
+	if p.depth >= maxIncludeDepth {
+		return "", fmt.Errorf("%s: template %q exceeds the maximum depth of %d", pipe, name, maxIncludeDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (p *Pipes) renderHTML(pipe, name string, t executor, data interface{}) (htmltemplate.HTML, error) {
+	s, err := p.render(pipe, name, t, data)
+	return htmltemplate.HTML(s), err
+}
+
+var includePipesInfo = map[string]PipeInfo{
+	"include": {
+		Description: "Renders a named template into a string that can be piped.",
+		Examples:    []string{`{{include "job" . | indent 2}}`},
+	},
+	"tpl": {
+		Description: "Renders a string as a template, it can use the named templates.",
+		Examples:    []string{`{{tpl .Config.Message .}}`},
+	},
+}
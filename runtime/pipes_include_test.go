@@ -0,0 +1,82 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Include Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+	parse := func(text string) *template.Template {
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		pipes.SetTemplate(tmpl)
+		return tmpl
+	}
+
+	Context("when validating include method", func() {
+		It("should pipe a named template", func() {
+			tmpl := parse(`{{define "job"}}name: {{.}}
+plan: []{{end}}jobs:
+{{include "job" . | indent 2}}`)
+			err := tmpl.Execute(buffer, "repo1")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`jobs:\n  name: repo1\n  plan: \[\]`))
+		})
+		It("should catch an undefined template", func() {
+			tmpl := parse(`{{include "job" .}}`)
+			err := tmpl.Execute(buffer, "repo1")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`include: template "job" is not defined`))
+		})
+		It("should catch a recursive template", func() {
+			tmpl := parse(`{{define "loop"}}{{include "loop" .}}{{end}}{{include "loop" .}}`)
+			err := tmpl.Execute(buffer, "repo1")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`template "loop" exceeds the maximum depth of 32`))
+		})
+	})
+	Context("when validating tpl method", func() {
+		It("should render a field with the named templates", func() {
+			tmpl := parse(`{{define "greet"}}Hello {{.}}{{end}}{{tpl .Message .Name | toUpper}}`)
+			err := tmpl.Execute(buffer, map[string]string{
+				"Message": `{{template "greet" .}}, see {{. | trimSuffix "-ci"}}`,
+				"Name":    "myproject-ci",
+			})
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`HELLO MYPROJECT-CI, SEE MYPROJECT`))
+		})
+		It("should render without a template", func() {
+			tmpl, err := tmpl.Parse(`{{tpl "{{. | quote}}" .}}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "a")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`"a"`))
+		})
+		It("should catch a recursive field", func() {
+			tmpl := parse(`{{tpl . .}}`)
+			err := tmpl.Execute(buffer, `{{tpl . .}}`)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`exceeds the maximum depth`))
+		})
+		It("should catch an invalid field", func() {
+			tmpl := parse(`{{tpl . .}}`)
+			err := tmpl.Execute(buffer, `{{.`)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
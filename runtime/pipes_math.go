@@ -0,0 +1,290 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var (
+	errOverflow     = errors.New("integer overflow")
+	errDivideByZero = errors.New("integer divide by zero")
+)
+
+//maxSeqLen guards against ranging over an accidental huge count
+const maxSeqLen = 1000000
+
+//mathPipes are helper functions for arithmetic; the piped value is the left operand
+//e.g. {{.Index | add 8080}} => .Index + 8080, {{.Count | sub 1}} => .Count - 1
+func mathPipes() template.FuncMap {
+	return template.FuncMap{
+		"add": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+					return 0, errOverflow
+				}
+				return x + y, nil
+			})
+		},
+		"sub": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if (y < 0 && x > math.MaxInt64+y) || (y > 0 && x < math.MinInt64+y) {
+					return 0, errOverflow
+				}
+				return x - y, nil
+			})
+		},
+		"mul": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if x == 0 || y == 0 {
+					return 0, nil
+				}
+				r := x * y
+				if r/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64) {
+					return 0, errOverflow
+				}
+				return r, nil
+			})
+		},
+		"div": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if y == 0 {
+					return 0, errDivideByZero
+				}
+				if x == math.MinInt64 && y == -1 {
+					return 0, errOverflow
+				}
+				return x / y, nil
+			})
+		},
+		"mod": func(b, a interface{}) (int64, error) {
+			return intOp(a, b, func(x, y int64) (int64, error) {
+				if y == 0 {
+					return 0, errDivideByZero
+				}
+				if y == -1 {
+					return 0, nil
+				}
+				return x % y, nil
+			})
+		},
+		//e.g. max 1 .A .B  => the largest value
+		"max": func(a interface{}, rest ...interface{}) (int64, error) {
+			return intFold(a, rest, func(x, y int64) bool { return y > x })
+		},
+		"min": func(a interface{}, rest ...interface{}) (int64, error) {
+			return intFold(a, rest, func(x, y int64) bool { return y < x })
+		},
+		"floor": func(v interface{}) (float64, error) {
+			f, err := toFloat64(v)
+			return math.Floor(f), err
+		},
+		"ceil": func(v interface{}) (float64, error) {
+			f, err := toFloat64(v)
+			return math.Ceil(f), err
+		},
+		"atoi": func(v interface{}) (int, error) {
+			return strconv.Atoi(toString(v))
+		},
+		//e.g. formatFloat 2 3.14159  => "3.14"
+		"formatFloat": func(prec int, v interface{}) (string, error) {
+			f, err := toFloat64(v)
+			if err != nil {
+				return "", err
+			}
+			return strconv.FormatFloat(f, 'f', prec, 64), nil
+		},
+		//e.g. humanizeBytes 1572864  => "1.5 MiB"
+		"humanizeBytes": func(v interface{}) (string, error) {
+			n, err := toInt64(v)
+			if err != nil {
+				return "", err
+			}
+			return humanizeBytes(n), nil
+		},
+		//e.g. seq 3  => [1 2 3], seq 2 4  => [2 3 4], seq 10 -5 1  => [10 5]
+		"seq": func(a ...int) ([]int, error) {
+			first, step, last := 1, 1, 0
+			switch len(a) {
+			case 1:
+				last = a[0]
+			case 2:
+				first, last = a[0], a[1]
+			case 3:
+				first, step, last = a[0], a[1], a[2]
+			default:
+				return nil, fmt.Errorf("seq: wrong number of args: got %d want 1 to 3", len(a))
+			}
+			return sequence(first, step, last)
+		},
+		//e.g. until 3  => [0 1 2]
+		"until": func(n int) ([]int, error) {
+			if n <= 0 {
+				return []int{}, nil
+			}
+			return sequence(0, 1, n-1)
+		},
+	}
+}
+
+func intOp(a, b interface{}, op func(x, y int64) (int64, error)) (int64, error) {
+	x, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toInt64(b)
+	if err != nil {
+		return 0, err
+	}
+	return op(x, y)
+}
+
+func intFold(a interface{}, rest []interface{}, replace func(x, y int64) bool) (int64, error) {
+	out, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range rest {
+		n, err := toInt64(v)
+		if err != nil {
+			return 0, err
+		}
+		if replace(out, n) {
+			out = n
+		}
+	}
+	return out, nil
+}
+
+func sequence(first, step, last int) ([]int, error) {
+	if step == 0 {
+		return nil, errors.New("seq: step cannot be zero")
+	}
+	out := []int{}
+	for i := first; (step > 0 && i <= last) || (step < 0 && i >= last); i += step {
+		if len(out) == maxSeqLen {
+			return nil, fmt.Errorf("seq: more than %d values", maxSeqLen)
+		}
+		out = append(out, i)
+	}
+	return out, nil
+}
+
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	f := float64(n)
+	exp := 0
+	for math.Abs(f) >= unit && exp < 6 {
+		f /= unit
+		exp++
+	}
+	return fmt.Sprintf("%s %ciB", strings.TrimSuffix(strconv.FormatFloat(f, 'f', 1, 64), ".0"), "KMGTPE"[exp-1])
+}
+
+//toInt64 accepts any integer kind, an integral float or a numeric string
+func toInt64(v interface{}) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, errOverflow
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("cannot convert %v to int", f)
+		}
+		if f >= math.MaxInt64 || f < math.MinInt64 {
+			return 0, errOverflow
+		}
+		return int64(f), nil
+	case reflect.String:
+		return strconv.ParseInt(rv.String(), 10, 64)
+	}
+	return 0, fmt.Errorf("cannot convert %T to int", v)
+}
+
+//toFloat64 accepts any integer or float kind or a numeric string
+func toFloat64(v interface{}) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(rv.String(), 64)
+	}
+	return 0, fmt.Errorf("cannot convert %T to float", v)
+}
+
+var mathPipesInfo = map[string]PipeInfo{
+	"add": {
+		Description: "Adds to the piped integer.",
+		Examples:    []string{`{{.Index | add 8080}}`},
+	},
+	"sub": {
+		Description: "Subtracts from the piped integer.",
+		Examples:    []string{`{{.Count | sub 1}}`},
+	},
+	"mul": {
+		Description: "Multiplies the piped integer.",
+		Examples:    []string{`{{.Count | mul 2}}`},
+	},
+	"div": {
+		Description: "Divides the piped integer.",
+		Examples:    []string{`{{.Count | div 2}}`},
+	},
+	"mod": {
+		Description: "Returns the remainder of dividing the piped integer.",
+		Examples:    []string{`{{.Index | mod 2}}`},
+	},
+	"max": {
+		Description: "Returns the largest integer.",
+		Examples:    []string{`{{max 1 .A .B}}`},
+	},
+	"min": {
+		Description: "Returns the smallest integer.",
+		Examples:    []string{`{{min 1 .A .B}}`},
+	},
+	"floor": {
+		Description: "Returns the greatest integer value less than or equal to the number.",
+		Examples:    []string{`{{.Ratio | floor}}`},
+	},
+	"ceil": {
+		Description: "Returns the least integer value greater than or equal to the number.",
+		Examples:    []string{`{{.Ratio | ceil}}`},
+	},
+	"atoi": {
+		Description: "Converts a string to an integer.",
+		Examples:    []string{`{{.Port | atoi}}`},
+	},
+	"formatFloat": {
+		Description: "Formats a number with the precision.",
+		Examples:    []string{`{{.Ratio | formatFloat 2}}`},
+	},
+	"humanizeBytes": {
+		Description: "Formats a number of bytes with binary units.",
+		Examples:    []string{`{{.Size | humanizeBytes}}`},
+	},
+	"seq": {
+		Description: "Returns the integers of seq LAST, seq FIRST LAST or seq FIRST STEP LAST.",
+		Examples:    []string{`{{range seq 1 3}}...{{end}}`},
+	},
+	"until": {
+		Description: "Returns the integers from 0 to the count - 1.",
+		Examples:    []string{`{{range .Count | until}}...{{end}}`},
+	},
+}
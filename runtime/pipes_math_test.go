@@ -0,0 +1,122 @@
+package runtime_test
+
+import (
+	"math"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Math Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate arithmetic methods with the piped value as the left operand", func() {
+		const text = `{{ . | add 8080 }} {{ . | sub 1 }} {{ . | mul 3 }} {{ . | div 2 }} {{ . | mod 4 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, 7)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`8087 6 21 3 3`))
+	})
+	It("should accept integral floats and numeric strings", func() {
+		const text = `{{ .A | add .B }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"A": float64(2), "B": "40"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`42`))
+	})
+	It("should return divide by zero as an error", func() {
+		const text = `{{ . | div 0 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, 7)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("integer divide by zero"))
+	})
+	It("should return overflow as an error", func() {
+		const text = `{{ . | add 1 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, int64(math.MaxInt64))
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("integer overflow"))
+	})
+	It("should validate max and min methods", func() {
+		const text = `{{ max 3 .A .B }} {{ min 3 .A .B }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]int{"A": 9, "B": -2})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`9 -2`))
+	})
+	It("should validate floor and ceil methods", func() {
+		const text = `{{ . | floor }} {{ . | ceil }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, 1.5)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`1 2`))
+	})
+	It("should validate atoi method", func() {
+		const text = `{{ . | atoi | add 1 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "41")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`42`))
+	})
+	It("should convert an int value with atoi method", func() {
+		const text = `{{ .Port | atoi | add 1 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"Port": 8080})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`8081`))
+	})
+	It("should validate formatFloat method", func() {
+		const text = `{{ . | formatFloat 2 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, 3.14159)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`3.14`))
+	})
+	It("should validate humanizeBytes method", func() {
+		const text = `{{ range . }}{{ . | humanizeBytes }}, {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, []int64{512, 1024, 1572864, 5 << 30})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`512 B, 1 KiB, 1.5 MiB, 5 GiB, `))
+	})
+	It("should validate seq method", func() {
+		const text = `{{ seq 3 }} {{ seq 2 4 }} {{ seq 10 -5 1 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[1 2 3\] \[2 3 4\] \[10 5\]`))
+	})
+	It("should validate until method for ranging over a count", func() {
+		const text = `{{ range . | until }}{{ . | add 8080 }} {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, 3)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`8080 8081 8082 `))
+	})
+})
@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"errors"
+	"reflect"
+	"text/template"
+)
+
+//missingPipes are helper functions for dealing with missing or empty data
+func missingPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. default "guest" .Name  => "guest" when .Name is empty
+		"default": func(d, v interface{}) (interface{}, error) {
+			if isEmpty(v) {
+				return d, nil
+			}
+			return v, nil
+		},
+		//e.g. coalesce .Name .Nick "guest"  => the first non-empty value
+		"coalesce": func(a ...interface{}) (interface{}, error) {
+			for _, v := range a {
+				if !isEmpty(v) {
+					return v, nil
+				}
+			}
+			return nil, nil
+		},
+		"empty": func(v interface{}) (bool, error) {
+			return isEmpty(v), nil
+		},
+		//e.g. ternary "on" "off" .Enabled
+		"ternary": func(t, f interface{}, cond bool) (interface{}, error) {
+			if cond {
+				return t, nil
+			}
+			return f, nil
+		},
+		//e.g. required "password is missing" .Private.Password
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if isEmpty(v) {
+				return nil, errors.New(msg)
+			}
+			return v, nil
+		},
+		"fail": func(msg string) (string, error) {
+			return "", errors.New(msg)
+		},
+	}
+}
+
+//isEmpty reports whether v is nil or the zero value of its type
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String, reflect.Chan:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return rv.Complex() == 0
+	case reflect.Interface, reflect.Ptr, reflect.Func:
+		return rv.IsNil()
+	case reflect.Struct:
+		return reflect.DeepEqual(v, reflect.Zero(rv.Type()).Interface())
+	}
+	return false
+}
+
+var missingPipesInfo = map[string]PipeInfo{
+	"default": {
+		Description: "Returns the default when the value is empty.",
+		Examples:    []string{`{{.Name | default "guest"}}`},
+	},
+	"coalesce": {
+		Description: "Returns the first non-empty value.",
+		Examples:    []string{`{{coalesce .Name .Nick "guest"}}`},
+	},
+	"empty": {
+		Description: "Reports whether the value is nil or the zero value of its type.",
+		Examples:    []string{`{{if .List | empty}}...{{end}}`},
+	},
+	"ternary": {
+		Description: "Returns the first value when the condition is true, otherwise the second.",
+		Examples:    []string{`{{.Enabled | ternary "on" "off"}}`},
+	},
+	"required": {
+		Description: "Aborts the render with the message when the value is empty.",
+		Examples:    []string{`{{.Private.Password | required "password is missing"}}`},
+	},
+	"fail": {
+		Description: "Aborts the render with the message.",
+		Examples:    []string{`{{fail "unsupported platform"}}`},
+	},
+}
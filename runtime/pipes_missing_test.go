@@ -0,0 +1,87 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Missing Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate default method", func() {
+		const text = `{{ .A | default "guest" }}-{{ .B | default "guest" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]string{"A": "", "B": "jane"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`guest-jane`))
+	})
+	It("should validate coalesce method", func() {
+		const text = `{{ coalesce .A .B "last" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"A": 0, "B": []string{"b"}})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[b\]`))
+	})
+	It("should validate empty method", func() {
+		const text = `{{ .A | empty }} {{ .B | empty }} {{ .C | empty }} {{ .D | empty }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"A": "", "B": false, "C": map[string]string{}, "D": 1,
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true true true false`))
+	})
+	It("should validate ternary method", func() {
+		const text = `{{ . | ternary "on" "off" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, false)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`off`))
+	})
+	Context("when validating a required method", func() {
+		It("should pass through a non-empty value", func() {
+			const text = `{{ . | required "value is missing" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "secret")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`secret`))
+		})
+		It("should fail with the message and the template location", func() {
+			const text = "line1\n{{ . | required \"value is missing\" }}"
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("tester:2:"))
+			Expect(err.Error()).To(ContainSubstring("value is missing"))
+		})
+	})
+	It("should validate fail method", func() {
+		const text = `{{ if not . }}{{ fail "unsupported platform" }}{{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, false)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("tester:1:"))
+		Expect(err.Error()).To(ContainSubstring("unsupported platform"))
+	})
+})
@@ -0,0 +1,172 @@
+package runtime
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"strings"
+	"text/template"
+)
+
+//networkPipes are helper functions for computing addresses from an IP or a CIDR
+func networkPipes() template.FuncMap {
+	return template.FuncMap{
+		//e.g. cidrHost 5 "10.0.0.0/24"  => "10.0.0.5", a negative number counts from the end of the range
+		"cidrHost": func(hostnum int, v interface{}) (string, error) {
+			cidr := toString(v)
+			prefix, err := parseCIDR(cidr)
+			if err != nil {
+				return "", err
+			}
+			hostBits := uint(prefix.Addr().BitLen() - prefix.Bits())
+			size := new(big.Int).Lsh(big.NewInt(1), hostBits)
+			num := big.NewInt(int64(hostnum))
+			if hostnum < 0 {
+				num.Add(num, size)
+			}
+			if num.Sign() < 0 || num.Cmp(size) >= 0 {
+				return "", fmt.Errorf("cidrHost: host number %d is out of range for %s", hostnum, cidr)
+			}
+			addr, err := addToAddr(prefix.Addr(), num)
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+		//e.g. cidrSubnet 8 2 "10.0.0.0/16"  => "10.0.2.0/24"
+		"cidrSubnet": func(newbits, netnum int, v interface{}) (string, error) {
+			cidr := toString(v)
+			prefix, err := parseCIDR(cidr)
+			if err != nil {
+				return "", err
+			}
+			bits := prefix.Bits() + newbits
+			if newbits < 0 || bits > prefix.Addr().BitLen() {
+				return "", fmt.Errorf("cidrSubnet: cannot extend %s by %d bits", cidr, newbits)
+			}
+			if netnum < 0 || big.NewInt(int64(netnum)).Cmp(new(big.Int).Lsh(big.NewInt(1), uint(newbits))) >= 0 {
+				return "", fmt.Errorf("cidrSubnet: network number %d is out of range for %d new bits", netnum, newbits)
+			}
+			offset := new(big.Int).Lsh(big.NewInt(int64(netnum)), uint(prefix.Addr().BitLen()-bits))
+			addr, err := addToAddr(prefix.Addr(), offset)
+			if err != nil {
+				return "", err
+			}
+			return netip.PrefixFrom(addr, bits).String(), nil
+		},
+		//e.g. cidrNetmask "10.0.0.0/20"  => "255.255.240.0"
+		"cidrNetmask": func(v interface{}) (string, error) {
+			prefix, err := parseCIDR(toString(v))
+			if err != nil {
+				return "", err
+			}
+			return net.IP(net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen())).String(), nil
+		},
+		//e.g. ipAdd 10 "10.0.0.1"  => "10.0.0.11"
+		"ipAdd": func(n int, v interface{}) (string, error) {
+			addr, err := parseIP(toString(v))
+			if err != nil {
+				return "", err
+			}
+			addr, err = addToAddr(addr, big.NewInt(int64(n)))
+			if err != nil {
+				return "", err
+			}
+			return addr.String(), nil
+		},
+		//e.g. ipInRange "10.0.0.10" "10.0.0.20" .IP  => true when start <= IP <= end
+		"ipInRange": func(start, end string, v interface{}) (bool, error) {
+			addrs := make([]netip.Addr, 3)
+			for k, v := range []string{start, end, toString(v)} {
+				addr, err := parseIP(v)
+				if err != nil {
+					return false, err
+				}
+				addrs[k] = addr
+			}
+			if addrs[0].BitLen() != addrs[2].BitLen() || addrs[1].BitLen() != addrs[2].BitLen() {
+				return false, nil
+			}
+			return addrs[0].Compare(addrs[2]) <= 0 && addrs[2].Compare(addrs[1]) <= 0, nil
+		},
+		//e.g. cidrContains "10.0.0.0/16" .IP, the value can also be a CIDR e.g. "10.0.1.0/24"
+		"cidrContains": func(cidr string, v interface{}) (bool, error) {
+			prefix, err := parseCIDR(cidr)
+			if err != nil {
+				return false, err
+			}
+			s := toString(v)
+			if strings.Contains(s, "/") {
+				other, err := parseCIDR(s)
+				if err != nil {
+					return false, err
+				}
+				return other.Bits() >= prefix.Bits() && prefix.Contains(other.Addr()), nil
+			}
+			addr, err := parseIP(s)
+			if err != nil {
+				return false, err
+			}
+			return prefix.Contains(addr), nil
+		},
+	}
+}
+
+//parseCIDR returns the masked prefix of a CIDR e.g. "10.0.0.1/24" => 10.0.0.0/24
+func parseCIDR(cidr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", cidr)
+	}
+	return prefix.Masked(), nil
+}
+
+func parseIP(ip string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid IP address %q", ip)
+	}
+	return addr.Unmap(), nil
+}
+
+//addToAddr adds n to an address without leaving its address family
+func addToAddr(addr netip.Addr, n *big.Int) (netip.Addr, error) {
+	sum := new(big.Int).SetBytes(addr.AsSlice())
+	sum.Add(sum, n)
+	size := addr.BitLen() / 8
+	if sum.Sign() < 0 || len(sum.Bytes()) > size {
+		return netip.Addr{}, fmt.Errorf("%s %+d is out of the address range", addr, n)
+	}
+	buf := make([]byte, size)
+	sum.FillBytes(buf)
+	out, _ := netip.AddrFromSlice(buf)
+	return out, nil
+}
+
+var networkPipesInfo = map[string]PipeInfo{
+	"cidrHost": {
+		Description: "Returns the address of a host number within a CIDR, a negative number counts from the end.",
+		Examples:    []string{`{{"10.0.0.0/24" | cidrHost 5}}`},
+	},
+	"cidrSubnet": {
+		Description: "Returns the subnet of a CIDR extended by the new bits with the network number.",
+		Examples:    []string{`{{"10.1.0.0/16" | cidrSubnet 8 2}}`},
+	},
+	"cidrNetmask": {
+		Description: "Returns the netmask of a CIDR.",
+		Examples:    []string{`{{"10.0.0.0/20" | cidrNetmask}}`},
+	},
+	"ipAdd": {
+		Description: "Adds a number to an IP address.",
+		Examples:    []string{`{{"10.0.0.1" | ipAdd 10}}`},
+	},
+	"ipInRange": {
+		Description: "Reports whether an IP address is between the start and end addresses.",
+		Examples:    []string{`{{.IP | ipInRange "10.0.0.10" "10.0.0.20"}}`},
+	},
+	"cidrContains": {
+		Description: "Reports whether a CIDR contains an IP address or another CIDR.",
+		Examples:    []string{`{{.IP | cidrContains "10.0.0.0/16"}}`},
+	},
+}
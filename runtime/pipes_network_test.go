@@ -0,0 +1,122 @@
+package runtime_test
+
+import (
+	"net"
+	"net/netip"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Network Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	Context("when validating a cidrHost method", func() {
+		It("should count from the start and the end of the range", func() {
+			const text = `{{ . | cidrHost 1 }} {{ . | cidrHost -2 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "10.0.4.7/22")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`10.0.4.1 10.0.7.254`))
+		})
+		It("should support IPv6", func() {
+			const text = `{{ . | cidrHost 17 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "fd00::/64")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`fd00::11`))
+		})
+		It("should catch a host number outside of the range", func() {
+			const text = `{{ . | cidrHost 256 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "10.0.0.0/24")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("out of range"))
+		})
+	})
+	It("should validate cidrSubnet method", func() {
+		const text = `{{ . | cidrSubnet 8 2 }} {{ . | cidrSubnet 4 15 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "10.1.0.0/16")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`10.1.2.0/24 10.1.240.0/20`))
+	})
+	It("should validate cidrNetmask method", func() {
+		const text = `{{ . | cidrNetmask }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "10.0.0.0/20")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`255.255.240.0`))
+	})
+	Context("when validating an ipAdd method", func() {
+		It("should add and subtract across octets", func() {
+			const text = `{{ . | ipAdd 10 }} {{ . | ipAdd -256 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "10.0.1.250")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`10.0.2.4 10.0.0.250`))
+		})
+		It("should catch an overflow", func() {
+			const text = `{{ . | ipAdd 1 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "255.255.255.255")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("out of the address range"))
+		})
+	})
+	It("should validate ipInRange method", func() {
+		const text = `{{ range . }}{{ . | ipInRange "10.0.0.10" "10.0.0.20" }} {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, []string{"10.0.0.10", "10.0.0.21", "fd00::1"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true false false `))
+	})
+	It("should validate cidrContains method", func() {
+		const text = `{{ range . }}{{ . | cidrContains "10.0.0.0/16" }} {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, []string{"10.0.200.1", "10.1.0.1", "10.0.1.0/24", "10.0.0.0/8"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true false true false `))
+	})
+	It("should accept a Stringer value", func() {
+		const text = `{{ .Net | cidrNetmask }} {{ .IP | ipAdd 1 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{
+			"Net": netip.MustParsePrefix("10.0.0.0/20"),
+			"IP":  net.ParseIP("10.0.0.1"),
+		})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`255.255.240.0 10.0.0.2`))
+	})
+	It("should catch an invalid CIDR", func() {
+		const text = `{{ . | cidrNetmask }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "10.0.0.0")
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring(`invalid CIDR "10.0.0.0"`))
+	})
+})
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"text/template"
+)
+
+//ParsedURL is the result of urlParse e.g. {{(urlParse .Repo).Host}}
+type ParsedURL struct {
+	Scheme   string
+	User     string
+	Host     string
+	Port     string
+	Path     string
+	Query    url.Values
+	Fragment string
+
+	url *url.URL
+}
+
+func (u ParsedURL) String() string {
+	return u.url.String()
+}
+
+//pathPipes are helper functions for splitting and building slash separated paths and URLs
+func pathPipes() template.FuncMap {
+	return template.FuncMap{
+		"base": func(v interface{}) (string, error) {
+			return path.Base(toString(v)), nil
+		},
+		"dir": func(v interface{}) (string, error) {
+			return path.Dir(toString(v)), nil
+		},
+		"ext": func(v interface{}) (string, error) {
+			return path.Ext(toString(v)), nil
+		},
+		"clean": func(v interface{}) (string, error) {
+			return path.Clean(toString(v)), nil
+		},
+		//e.g. pathJoin "/etc" "app" .File  => "/etc/app/config.yml"
+		"pathJoin": func(elem ...interface{}) (string, error) {
+			return path.Join(toStrings(elem)...), nil
+		},
+		"urlParse": func(v interface{}) (ParsedURL, error) {
+			u, err := parseURL(toString(v))
+			if err != nil {
+				return ParsedURL{}, err
+			}
+			return ParsedURL{
+				Scheme:   u.Scheme,
+				User:     u.User.Username(),
+				Host:     u.Hostname(),
+				Port:     u.Port(),
+				Path:     u.Path,
+				Query:    u.Query(),
+				Fragment: u.Fragment,
+				url:      u,
+			}, nil
+		},
+		//e.g. urlJoin "https://github.com/jane" "repo1.git"  => "https://github.com/jane/repo1.git"
+		"urlJoin": func(base string, elem ...interface{}) (string, error) {
+			u, err := parseURL(base)
+			if err != nil {
+				return "", err
+			}
+			return u.JoinPath(toStrings(elem)...).String(), nil
+		},
+		//e.g. urlSetQuery "ref" "develop" .Repo  => "https://github.com/jane/repo1?ref=develop"
+		"urlSetQuery": func(key string, value, v interface{}) (string, error) {
+			u, err := parseURL(toString(v))
+			if err != nil {
+				return "", err
+			}
+			q := u.Query()
+			q.Set(key, toString(value))
+			u.RawQuery = q.Encode()
+			return u.String(), nil
+		},
+	}
+}
+
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %s", s, err.Error())
+	}
+	return u, nil
+}
+
+var pathPipesInfo = map[string]PipeInfo{
+	"base": {
+		Description: "Returns the last element of a path.",
+		Examples:    []string{`{{.File | base}}`},
+	},
+	"dir": {
+		Description: "Returns all but the last element of a path.",
+		Examples:    []string{`{{.File | dir}}`},
+	},
+	"ext": {
+		Description: "Returns the file name extension of a path.",
+		Examples:    []string{`{{.File | ext}}`},
+	},
+	"clean": {
+		Description: "Returns the shortest equivalent path.",
+		Examples:    []string{`{{.File | clean}}`},
+	},
+	"pathJoin": {
+		Description: "Joins the elements of a path.",
+		Examples:    []string{`{{.File | pathJoin "/etc" "app"}}`},
+	},
+	"urlParse": {
+		Description: "Parses a URL with Scheme, User, Host, Port, Path, Query and Fragment fields.",
+		Examples:    []string{`{{(.Repo | urlParse).Host}}`},
+	},
+	"urlJoin": {
+		Description: "Joins the elements to the path of a URL.",
+		Examples:    []string{`{{"repo1.git" | urlJoin "https://github.com/jane"}}`},
+	},
+	"urlSetQuery": {
+		Description: "Sets a query parameter of a URL.",
+		Examples:    []string{`{{.Repo | urlSetQuery "ref" .Branch}}`},
+	},
+}
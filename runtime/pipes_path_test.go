@@ -0,0 +1,83 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Path Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate base, dir, ext and clean methods", func() {
+		const text = `{{ . | base }} {{ . | dir }} {{ . | ext }} {{ . | clean }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "/etc//app/../app/config.yml")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`config.yml /etc/app .yml /etc/app/config.yml`))
+	})
+	It("should validate pathJoin method", func() {
+		const text = `{{ . | pathJoin "/etc" "app" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "config.yml")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`/etc/app/config.yml`))
+	})
+	It("should join int values", func() {
+		const text = `{{ .Port | pathJoin "/srv" .Version }} {{ .Port | urlSetQuery "port" .Port }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"Port": 8080, "Version": 2})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`/srv/2/8080 8080\?port=8080`))
+	})
+	Context("when validating an urlParse method", func() {
+		It("should return the URL fields", func() {
+			const text = `{{ with urlParse . }}{{.Scheme}}|{{.User}}|{{.Host}}|{{.Port}}|{{.Path}}|{{.Query.Get "ref"}}|{{.Fragment}}|{{.}}{{ end }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "https://git@github.com:8443/jane/repo1.git?ref=develop#readme")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`https\|git\|github.com\|8443\|/jane/repo1.git\|develop\|readme\|https://git@github.com:8443/jane/repo1.git\?ref=develop#readme`))
+		})
+		It("should catch an invalid URL", func() {
+			const text = `{{ urlParse . }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "http://[::1")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`invalid URL "http://[::1"`))
+		})
+	})
+	It("should validate urlJoin method", func() {
+		const text = `{{ . | urlJoin "https://github.com/jane/" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "repo1.git")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`https://github.com/jane/repo1.git`))
+	})
+	It("should validate urlSetQuery method", func() {
+		const text = `{{ . | urlSetQuery "ref" "develop" | urlSetQuery "depth" "1" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "https://github.com/jane/repo1?ref=master")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`https://github.com/jane/repo1\?depth=1&ref=develop`))
+	})
+})
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"text/template"
+)
+
+//regexCache holds the patterns compiled during a render
+type regexCache struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func (c *regexCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.Lock()
+	defer c.Unlock()
+	if re, ok := c.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err.Error())
+	}
+	if c.patterns == nil {
+		c.patterns = make(map[string]*regexp.Regexp)
+	}
+	c.patterns[pattern] = re
+	return re, nil
+}
+
+//regexPipes are helper functions for regular expressions, patterns are compiled once per Pipes
+func (p *Pipes) regexPipes() template.FuncMap {
+	return template.FuncMap{
+		"regexMatch": func(pattern string, v interface{}) (bool, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return false, err
+			}
+			return re.MatchString(toString(v)), nil
+		},
+		"regexFind": func(pattern string, v interface{}) (string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.FindString(toString(v)), nil
+		},
+		//e.g. regexFindAll "[0-9]+" -1 .Text  => all of the matches
+		"regexFindAll": func(pattern string, n int, v interface{}) ([]string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			out := re.FindAllString(toString(v), n)
+			if out == nil {
+				return []string{}, nil
+			}
+			return out, nil
+		},
+		//e.g. regexReplaceAll "(\\w+)@(\\w+)" "${2}/${1}" .Email  => capture groups are referenced with $1 or ${1}
+		"regexReplaceAll": func(pattern, repl string, v interface{}) (string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.ReplaceAllString(toString(v), repl), nil
+		},
+		//e.g. regexSplit "\\s*,\\s*" -1 .List
+		"regexSplit": func(pattern string, n int, v interface{}) ([]string, error) {
+			re, err := p.regexps.compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			return re.Split(toString(v), n), nil
+		},
+	}
+}
+
+var regexPipesInfo = map[string]PipeInfo{
+	"regexMatch": {
+		Description: "Reports whether the string matches the pattern.",
+		Examples:    []string{`{{if .Version | regexMatch "^v[0-9]+"}}...{{end}}`},
+	},
+	"regexFind": {
+		Description: "Returns the first match of the pattern.",
+		Examples:    []string{`{{.Text | regexFind "[0-9]+"}}`},
+	},
+	"regexFindAll": {
+		Description: "Returns at most n matches of the pattern, -1 for all of them.",
+		Examples:    []string{`{{.Text | regexFindAll "[0-9]+" -1}}`},
+	},
+	"regexReplaceAll": {
+		Description: "Replaces the matches of the pattern, capture groups are referenced with $1 or ${1}.",
+		Examples:    []string{`{{.Email | regexReplaceAll "(\\w+)@(\\w+)" "${2}/${1}"}}`},
+	},
+	"regexSplit": {
+		Description: "Splits the string into at most n parts around the matches of the pattern, -1 for all of them.",
+		Examples:    []string{`{{.List | regexSplit "\\s*,\\s*" -1}}`},
+	},
+}
@@ -0,0 +1,89 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Regex Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate regexMatch method", func() {
+		const text = `{{ . | regexMatch "^v[0-9]+" }} {{ . | regexMatch "^[0-9]+" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "v12")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true false`))
+	})
+	It("should match an int value", func() {
+		const text = `{{ .Port | regexMatch "^8" }} {{ .Port | regexReplaceAll "0" "1" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"Port": 8080})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true 8181`))
+	})
+	It("should validate regexFind method", func() {
+		const text = `{{ . | regexFind "[0-9]+" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "port 8080 and 9090")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`8080`))
+	})
+	It("should validate regexFindAll method", func() {
+		const text = `{{ . | regexFindAll "[0-9]+" -1 | join "," }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "port 8080 and 9090")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`8080,9090`))
+	})
+	It("should validate regexReplaceAll method with capture groups", func() {
+		const text = `{{ . | regexReplaceAll "(\\w+)@(\\w+)" "${2}/${1}" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "jane@example")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`example/jane`))
+	})
+	It("should validate regexSplit method", func() {
+		const text = `{{ . | regexSplit "\\s*,\\s*" -1 | join "|" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "a , b,c")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`a\|b\|c`))
+	})
+	It("should reuse a compiled pattern within the same render", func() {
+		const text = `{{ range . }}{{ . | regexMatch "^a" }} {{ end }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, []string{"ab", "ba", "ac"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true false true `))
+	})
+	It("should name the pattern that fails to compile", func() {
+		const text = `{{ . | regexMatch "a(b" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "ab")
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring(`invalid pattern "a(b"`))
+	})
+})
@@ -0,0 +1,351 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//SemVer is a parsed semantic version e.g. {{(semver "v1.2.3-rc.1").Minor}} => 2
+type SemVer struct {
+	Major, Minor, Patch int64
+	Prerelease          string
+	Metadata            string
+	Original            string
+}
+
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Metadata != "" {
+		s += "+" + v.Metadata
+	}
+	return s
+}
+
+//Compare returns -1, 0 or 1 following the semver precedence rules, metadata is ignored
+func (v SemVer) Compare(o SemVer) int {
+	for _, d := range []int64{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+//semverPipes are helper functions for parsing, comparing and sorting semantic versions
+func semverPipes() template.FuncMap {
+	return template.FuncMap{
+		"semver": func(v interface{}) (SemVer, error) {
+			return toSemVer(v)
+		},
+		//e.g. semverCompare ">=1.2, <2 || ^3.1" .Version  => true when the version satisfies the constraint
+		"semverCompare": func(constraint string, v interface{}) (bool, error) {
+			c, err := parseSemVerConstraint(constraint)
+			if err != nil {
+				return false, err
+			}
+			version, err := toSemVer(v)
+			if err != nil {
+				return false, err
+			}
+			return c.check(version), nil
+		},
+		//e.g. semverBump "minor" "1.2.3"  => 1.3.0
+		"semverBump": func(part string, v interface{}) (SemVer, error) {
+			version, err := toSemVer(v)
+			if err != nil {
+				return SemVer{}, err
+			}
+			out := SemVer{Major: version.Major, Minor: version.Minor, Patch: version.Patch}
+			//a prerelease of the bumped version is bumped to its release e.g. minor 1.3.0-rc.1 => 1.3.0
+			pre := version.Prerelease != ""
+			switch part {
+			case "major":
+				if !pre || out.Minor != 0 || out.Patch != 0 {
+					out.Major++
+				}
+				out.Minor, out.Patch = 0, 0
+			case "minor":
+				if !pre || out.Patch != 0 {
+					out.Minor++
+				}
+				out.Patch = 0
+			case "patch":
+				if !pre {
+					out.Patch++
+				}
+			default:
+				return SemVer{}, fmt.Errorf("semverBump: unknown part %q (major, minor or patch)", part)
+			}
+			out.Original = out.String()
+			return out, nil
+		},
+		//e.g. semverSort .Tags  => the tags from the lowest to the highest version
+		"semverSort": func(a interface{}) ([]string, error) {
+			rv := reflect.ValueOf(a)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return nil, fmt.Errorf("semverSort: cannot sort %T", a)
+			}
+			versions := make([]SemVer, rv.Len())
+			for i := range versions {
+				version, err := toSemVer(rv.Index(i).Interface())
+				if err != nil {
+					return nil, err
+				}
+				versions[i] = version
+			}
+			sort.SliceStable(versions, func(i, j int) bool {
+				return versions[i].Compare(versions[j]) < 0
+			})
+			out := make([]string, len(versions))
+			for k, v := range versions {
+				out[k] = v.Original
+			}
+			return out, nil
+		},
+	}
+}
+
+func toSemVer(v interface{}) (SemVer, error) {
+	switch s := v.(type) {
+	case SemVer:
+		return s, nil
+	case string:
+		return parseSemVer(s)
+	case fmt.Stringer:
+		return parseSemVer(s.String())
+	}
+	return SemVer{}, fmt.Errorf("cannot convert %T to semver", v)
+}
+
+//parseSemVer accepts an optional "v" prefix and missing minor or patch numbers e.g. "v1.2"
+func parseSemVer(s string) (SemVer, error) {
+	version, parts, err := parsePartialSemVer(s)
+	if err == nil && parts == 0 {
+		return SemVer{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+	return version, err
+}
+
+//parsePartialSemVer also returns how many of major, minor and patch are given before a wildcard ("x", "X" or "*")
+func parsePartialSemVer(s string) (SemVer, int, error) {
+	version := SemVer{Original: s}
+	invalid := fmt.Errorf("invalid semantic version %q", s)
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(rest, "+"); i >= 0 {
+		rest, version.Metadata = rest[:i], rest[i+1:]
+	}
+	if i := strings.Index(rest, "-"); i >= 0 {
+		rest, version.Prerelease = rest[:i], rest[i+1:]
+		if version.Prerelease == "" {
+			return SemVer{}, 0, invalid
+		}
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return SemVer{}, 0, invalid
+	}
+	numbers := []*int64{&version.Major, &version.Minor, &version.Patch}
+	for k, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			return version, k, nil
+		}
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return SemVer{}, 0, invalid
+		}
+		*numbers[k] = n
+	}
+	return version, len(parts), nil
+}
+
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	//a release has a higher precedence than its prereleases
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseInt(as[i], 10, 64)
+		bn, bErr := strconv.ParseInt(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(int64(len(as) - len(bs)))
+}
+
+func sign(n int64) int {
+	if n < 0 {
+		return -1
+	}
+	if n > 0 {
+		return 1
+	}
+	return 0
+}
+
+type semverComparator struct {
+	op      string
+	version SemVer
+}
+
+func (c semverComparator) check(v SemVer) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	}
+	return cmp <= 0
+}
+
+//semverConstraint is a list of "||" alternatives of "," separated comparators
+type semverConstraint [][]semverComparator
+
+//check follows the common convention that a prerelease only satisfies
+//a group of comparators that mentions a prerelease of the same version
+func (c semverConstraint) check(v SemVer) bool {
+	for _, group := range c {
+		ok := true
+		prerelease := v.Prerelease == ""
+		for _, comparator := range group {
+			if !comparator.check(v) {
+				ok = false
+				break
+			}
+			cv := comparator.version
+			if cv.Prerelease != "" && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+				prerelease = true
+			}
+		}
+		if ok && prerelease {
+			return true
+		}
+	}
+	return false
+}
+
+//parseSemVerConstraint supports =, !=, >, >=, <, <=, ~, ^ and wildcards e.g. ">=1.2, <2 || 3.x"
+func parseSemVerConstraint(s string) (semverConstraint, error) {
+	out := semverConstraint{}
+	for _, alternative := range strings.Split(s, "||") {
+		group := []semverComparator{}
+		for _, expr := range strings.Split(alternative, ",") {
+			comparators, err := parseSemVerComparator(strings.TrimSpace(expr))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", s, err.Error())
+			}
+			group = append(group, comparators...)
+		}
+		out = append(out, group)
+	}
+	return out, nil
+}
+
+func parseSemVerComparator(expr string) ([]semverComparator, error) {
+	op := ""
+	for _, o := range []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(expr, o) {
+			op = o
+			break
+		}
+	}
+	version, parts, err := parsePartialSemVer(strings.TrimSpace(strings.TrimPrefix(expr, op)))
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		return []semverComparator{{">=", SemVer{}}}, nil
+	}
+	wildcard := parts < 3
+	//upper is the first version beyond the range of the unspecified parts
+	upper := func(level int) SemVer {
+		switch level {
+		case 0:
+			return SemVer{Major: version.Major + 1}
+		case 1:
+			return SemVer{Major: version.Major, Minor: version.Minor + 1}
+		}
+		return SemVer{Major: version.Major, Minor: version.Minor, Patch: version.Patch + 1}
+	}
+	switch op {
+	case "", "=", "==":
+		if !wildcard {
+			return []semverComparator{{"=", version}}, nil
+		}
+		return []semverComparator{{">=", version}, {"<", upper(parts - 1)}}, nil
+	case "~":
+		if parts > 2 {
+			parts = 2
+		}
+		return []semverComparator{{">=", version}, {"<", upper(parts - 1)}}, nil
+	case "^":
+		level := 0
+		if version.Major == 0 && parts > 1 {
+			level = 1
+			if version.Minor == 0 && parts > 2 {
+				level = 2
+			}
+		}
+		return []semverComparator{{">=", version}, {"<", upper(level)}}, nil
+	case ">":
+		if wildcard {
+			return []semverComparator{{">=", upper(parts - 1)}}, nil
+		}
+	case "<=":
+		if wildcard {
+			return []semverComparator{{"<", upper(parts - 1)}}, nil
+		}
+	}
+	return []semverComparator{{op, version}}, nil
+}
+
+var semverPipesInfo = map[string]PipeInfo{
+	"semver": {
+		Description: "Parses a semantic version with Major, Minor, Patch, Prerelease and Metadata fields.",
+		Examples:    []string{`{{(.Version | semver).Minor}}`},
+	},
+	"semverCompare": {
+		Description: "Reports whether a semantic version satisfies the constraint.",
+		Examples:    []string{`{{if .Version | semverCompare ">=1.2, <2 || ^3.1"}}...{{end}}`},
+	},
+	"semverBump": {
+		Description: "Bumps the major, minor or patch part of a semantic version, a prerelease of that release is bumped to the release.",
+		Examples:    []string{`{{.Version | semverBump "minor"}}`},
+	},
+	"semverSort": {
+		Description: "Sorts a list of semantic versions from the lowest to the highest.",
+		Examples:    []string{`{{.Tags | semverSort}}`},
+	},
+}
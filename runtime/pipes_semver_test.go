@@ -0,0 +1,105 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Semver Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	Context("when validating a semver method", func() {
+		It("should parse the version fields", func() {
+			const text = `{{ with semver . }}{{.Major}}|{{.Minor}}|{{.Patch}}|{{.Prerelease}}|{{.Metadata}}{{ end }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "v1.22.3-rc.1+build.5")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`1\|22\|3\|rc.1\|build.5`))
+		})
+		It("should catch an invalid version", func() {
+			const text = `{{ semver . }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "1.2.3.4")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`invalid semantic version "1.2.3.4"`))
+		})
+	})
+	Context("when validating a semverCompare method", func() {
+		expectCompare := func(constraint string, versions []string, expected string) {
+			text := `{{ range . }}{{ . | semverCompare "` + constraint + `" }} {{ end }}`
+			tmpl, err := template.New("tester").Funcs(pipes.Map).Parse(text)
+			Expect(err).To(BeNil())
+			buffer := gbytes.NewBuffer()
+			err = tmpl.Execute(buffer, versions)
+			Expect(err).To(BeNil())
+			Expect(string(buffer.Contents())).To(Equal(expected))
+		}
+		It("should support ranges", func() {
+			expectCompare(">=1.2, <2", []string{"1.1.9", "1.2.0", "1.9.9", "2.0.0", "2.0.0-rc.1"}, "false true true false false ")
+		})
+		It("should support alternatives", func() {
+			expectCompare("<1 || >=3", []string{"0.9.0", "2.0.0", "3.1.0"}, "true false true ")
+		})
+		It("should support tilde and caret", func() {
+			expectCompare("~1.2.3", []string{"1.2.9", "1.3.0"}, "true false ")
+			expectCompare("^1.2.3", []string{"1.9.0", "2.0.0"}, "true false ")
+			expectCompare("^0.2.3", []string{"0.2.9", "0.3.0"}, "true false ")
+		})
+		It("should support wildcards", func() {
+			expectCompare("1.2.x", []string{"1.2.7", "1.3.0"}, "true false ")
+			expectCompare("*", []string{"0.0.1", "9.9.9"}, "true true ")
+		})
+		It("should only match a prerelease with a prerelease constraint", func() {
+			expectCompare(">=2.0.0-rc.1", []string{"2.0.0-rc.2", "2.0.0-alpha", "2.1.0-rc.1"}, "true false false ")
+		})
+		It("should catch an invalid constraint", func() {
+			const text = `{{ . | semverCompare ">=a" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "1.0.0")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`invalid constraint ">=a"`))
+		})
+	})
+	It("should validate semverBump method", func() {
+		const text = `{{ . | semverBump "major" }} {{ . | semverBump "minor" }} {{ . | semverBump "patch" }} {{ "1.0.0-rc.1" | semverBump "patch" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "v1.2.3")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`2.0.0 1.3.0 1.2.4 1.0.0`))
+	})
+	It("should validate semverBump method with a prerelease", func() {
+		const text = `{{ . | semverBump "major" }} {{ . | semverBump "minor" }} {{ . | semverBump "patch" }} ` +
+			`{{ "2.0.0-beta" | semverBump "major" }} {{ "1.3.1-rc.1" | semverBump "minor" }} {{ "1.3.1-rc.1" | semverBump "major" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "1.3.0-rc.1")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`2.0.0 1.3.0 1.3.0 2.0.0 1.4.0 2.0.0`))
+	})
+	It("should validate semverSort method", func() {
+		const text = `{{ . | semverSort | join "," }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, []string{"v1.10.0", "v1.2.0", "v1.2.0-rc.10", "v1.2.0-rc.2", "v0.9.1"})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`v0.9.1,v1.2.0-rc.2,v1.2.0-rc.10,v1.2.0,v1.10.0`))
+	})
+})
@@ -0,0 +1,299 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+//stringPipes are helper functions for trimming, casing, quoting and formatting strings,
+//the lengths and positions count characters rather than bytes
+func stringPipes() template.FuncMap {
+	return template.FuncMap{
+		"trim": func(v interface{}) (interface{}, error) {
+			return keepHTML(v, strings.TrimSpace(toString(v))), nil
+		},
+		"trimPrefix": func(prefix string, v interface{}) (string, error) {
+			return strings.TrimPrefix(toString(v), prefix), nil
+		},
+		"trimSuffix": func(suffix string, v interface{}) (string, error) {
+			return strings.TrimSuffix(toString(v), suffix), nil
+		},
+		//e.g. trimAll "/" "/jane/repo1/"  => "jane/repo1"
+		"trimAll": func(cutset string, v interface{}) (string, error) {
+			return strings.Trim(toString(v), cutset), nil
+		},
+		//e.g. title "hello wide world"  => "Hello Wide World"
+		"title": func(v interface{}) (string, error) {
+			r := []rune(toString(v))
+			for k, v := range r {
+				if k == 0 || unicode.IsSpace(r[k-1]) {
+					r[k] = unicode.ToTitle(v)
+				}
+			}
+			return string(r), nil
+		},
+		//e.g. camelCase "my-repo_name"  => "myRepoName"
+		"camelCase": func(v interface{}) (string, error) {
+			words := splitWords(toString(v))
+			for k, v := range words {
+				if k == 0 {
+					words[k] = strings.ToLower(v)
+					continue
+				}
+				r, size := utf8.DecodeRuneInString(v)
+				words[k] = string(unicode.ToUpper(r)) + strings.ToLower(v[size:])
+			}
+			return strings.Join(words, ""), nil
+		},
+		//e.g. snakeCase "HTTPServerName"  => "http_server_name"
+		"snakeCase": func(v interface{}) (string, error) {
+			return strings.ToLower(strings.Join(splitWords(toString(v)), "_")), nil
+		},
+		"kebabCase": func(v interface{}) (string, error) {
+			return strings.ToLower(strings.Join(splitWords(toString(v)), "-")), nil
+		},
+		//e.g. quote .Name  => "\"jane\"" with go escaping
+		"quote": func(v interface{}) (string, error) {
+			return strconv.Quote(toString(v)), nil
+		},
+		//e.g. squote "it's"  => "'it''s'", a single quote is escaped as in YAML and SQL
+		"squote": func(v interface{}) (string, error) {
+			return "'" + strings.Replace(toString(v), "'", "''", -1) + "'", nil
+		},
+		"repeat": func(n int, v interface{}) (string, error) {
+			if n < 0 {
+				return "", fmt.Errorf("repeat: negative count %d", n)
+			}
+			return strings.Repeat(toString(v), n), nil
+		},
+		//e.g. substr 0 4 "goflat"  => "gofl", a negative end is the end of the string
+		"substr": func(start, end int, v interface{}) (string, error) {
+			s := toString(v)
+			r := []rune(s)
+			if end < 0 || end > len(r) {
+				end = len(r)
+			}
+			if start < 0 || start > end {
+				return "", fmt.Errorf("substr: invalid range [%d:%d] of %q", start, end, s)
+			}
+			return string(r[start:end]), nil
+		},
+		//e.g. truncate 4 "goflat"  => "gofl"
+		"truncate": func(n int, v interface{}) (string, error) {
+			s := toString(v)
+			if n < 0 {
+				return "", fmt.Errorf("truncate: negative length %d", n)
+			}
+			r := []rune(s)
+			if n < len(r) {
+				return string(r[:n]), nil
+			}
+			return s, nil
+		},
+		//e.g. wrap 80 .Description, the words longer than the width are not broken
+		"wrap": func(width int, v interface{}) (string, error) {
+			if width < 1 {
+				return "", fmt.Errorf("wrap: invalid width %d", width)
+			}
+			return wrapWords(width, toString(v)), nil
+		},
+		"contains": func(substr string, v interface{}) (bool, error) {
+			return strings.Contains(toString(v), substr), nil
+		},
+		"hasPrefix": func(prefix string, v interface{}) (bool, error) {
+			return strings.HasPrefix(toString(v), prefix), nil
+		},
+		"hasSuffix": func(suffix string, v interface{}) (bool, error) {
+			return strings.HasSuffix(toString(v), suffix), nil
+		},
+		//e.g. indent 4 .Private.Key  => every line is indented by 4 spaces
+		"indent": func(n int, v interface{}) (interface{}, error) {
+			if n < 0 {
+				return "", fmt.Errorf("indent: negative width %d", n)
+			}
+			pad := strings.Repeat(" ", n)
+			return keepHTML(v, pad+strings.Replace(toString(v), "\n", "\n"+pad, -1)), nil
+		},
+		//e.g. padLeft 5 "42"  => "   42" or padLeft 5 "0" "42"  => "00042"
+		"padLeft": func(width int, a ...interface{}) (string, error) {
+			pad, s, err := padArgs("padLeft", a)
+			if err != nil {
+				return "", err
+			}
+			return padding(width, pad, s) + s, nil
+		},
+		"padRight": func(width int, a ...interface{}) (string, error) {
+			pad, s, err := padArgs("padRight", a)
+			if err != nil {
+				return "", err
+			}
+			return s + padding(width, pad, s), nil
+		},
+	}
+}
+
+//splitWords splits a string into words on the characters other than letters and digits
+//and on the case changes e.g. "HTTPServer_name" => ["HTTP", "Server", "name"]
+func splitWords(s string) []string {
+	words := []string{}
+	r := []rune(s)
+	start := -1
+	for k, v := range r {
+		if !unicode.IsLetter(v) && !unicode.IsDigit(v) {
+			if start >= 0 {
+				words = append(words, string(r[start:k]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = k
+			continue
+		}
+		prev := r[k-1]
+		lowerToUpper := unicode.IsUpper(v) && !unicode.IsUpper(prev)
+		acronymEnd := unicode.IsUpper(v) && unicode.IsUpper(prev) && k+1 < len(r) && unicode.IsLower(r[k+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(r[start:k]))
+			start = k
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(r[start:]))
+	}
+	return words
+}
+
+func wrapWords(width int, s string) string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line, size := "", 0
+		for _, word := range strings.Fields(paragraph) {
+			n := utf8.RuneCountInString(word)
+			if size > 0 && size+1+n > width {
+				lines = append(lines, line)
+				line, size = "", 0
+			}
+			if size > 0 {
+				line += " "
+				size++
+			}
+			line += word
+			size += n
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+//padArgs splits the args of padLeft and padRight into the optional pad, a space by default, and the piped string
+func padArgs(name string, a []interface{}) (string, string, error) {
+	switch len(a) {
+	case 1:
+		return " ", toString(a[0]), nil
+	case 2:
+		pad := toString(a[0])
+		if pad == "" {
+			return "", "", errors.New(name + ": empty pad")
+		}
+		return pad, toString(a[1]), nil
+	}
+	return "", "", fmt.Errorf("%s: wrong number of args: got %d want 2 or 3", name, len(a)+1)
+}
+
+func padding(width int, pad, s string) string {
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(strings.Repeat(pad, n))
+	return string(r[:n])
+}
+
+var stringPipesInfo = map[string]PipeInfo{
+	"trim": {
+		Description: "Removes the leading and trailing white space.",
+		Examples:    []string{`{{.Name | trim}}`},
+	},
+	"trimPrefix": {
+		Description: "Removes a leading prefix.",
+		Examples:    []string{`{{.Branch | trimPrefix "refs/heads/"}}`},
+	},
+	"trimSuffix": {
+		Description: "Removes a trailing suffix.",
+		Examples:    []string{`{{.Repo | trimSuffix ".git"}}`},
+	},
+	"trimAll": {
+		Description: "Removes the leading and trailing characters of a cutset.",
+		Examples:    []string{`{{.Path | trimAll "/"}}`},
+	},
+	"title": {
+		Description: "Capitalizes the first letter of every word.",
+		Examples:    []string{`{{.Name | title}}`},
+	},
+	"camelCase": {
+		Description: "Converts a string to camelCase.",
+		Examples:    []string{`{{.Name | camelCase}}`},
+	},
+	"snakeCase": {
+		Description: "Converts a string to snake_case.",
+		Examples:    []string{`{{.Name | snakeCase}}`},
+	},
+	"kebabCase": {
+		Description: "Converts a string to kebab-case.",
+		Examples:    []string{`{{.Name | kebabCase}}`},
+	},
+	"quote": {
+		Description: "Wraps a value in double quotes with Go escaping.",
+		Examples:    []string{`{{.Private.Password | quote}}`},
+	},
+	"squote": {
+		Description: "Wraps a value in single quotes, a single quote is escaped as two.",
+		Examples:    []string{`{{.Private.Password | squote}}`},
+	},
+	"repeat": {
+		Description: "Repeats a string a number of times.",
+		Examples:    []string{`{{"-" | repeat 20}}`},
+	},
+	"substr": {
+		Description: "Returns the characters from start to end, a negative end is the end of the string.",
+		Examples:    []string{`{{.Commit | substr 0 7}}`},
+	},
+	"truncate": {
+		Description: "Keeps the first characters of a string.",
+		Examples:    []string{`{{.Description | truncate 80}}`},
+	},
+	"wrap": {
+		Description: "Wraps the words of a string at a width.",
+		Examples:    []string{`{{.Description | wrap 80}}`},
+	},
+	"contains": {
+		Description: "Reports whether a string contains a substring.",
+		Examples:    []string{`{{if .Repo | contains "github.com"}}...{{end}}`},
+	},
+	"hasPrefix": {
+		Description: "Reports whether a string begins with a prefix.",
+		Examples:    []string{`{{if .Branch | hasPrefix "release/"}}...{{end}}`},
+	},
+	"hasSuffix": {
+		Description: "Reports whether a string ends with a suffix.",
+		Examples:    []string{`{{if .Repo | hasSuffix ".git"}}...{{end}}`},
+	},
+	"indent": {
+		Description: "Indents every line of a string by a number of spaces.",
+		Examples:    []string{`{{include "job" . | indent 4}}`},
+	},
+	"padLeft": {
+		Description: "Pads a string on the left to a width with spaces or an optional pad.",
+		Examples:    []string{`{{.Index | padLeft 5 "0"}}`},
+	},
+	"padRight": {
+		Description: "Pads a string on the right to a width with spaces or an optional pad.",
+		Examples:    []string{`{{.Name | padRight 20}}`},
+	},
+}
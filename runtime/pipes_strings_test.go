@@ -0,0 +1,151 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("String Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate trim methods", func() {
+		const text = `[{{ . | trim }}]{{ "refs/heads/main" | trimPrefix "refs/heads/" }}|{{ "repo1.git" | trimSuffix ".git" }}|{{ "/jane/repo1/" | trimAll "/" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "  a b \n")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[a b\]main\|repo1\|jane/repo1`))
+	})
+	It("should validate title method", func() {
+		const text = `{{ . | title }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "hello wide-world élan")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`Hello Wide-world Élan`))
+	})
+	Context("when changing the case of words", func() {
+		It("should split on separators and case changes", func() {
+			const text = `{{ . | camelCase }} {{ . | snakeCase }} {{ . | kebabCase }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "HTTPServer_name-v2 ID")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`httpServerNameV2Id http_server_name_v2_id http-server-name-v2-id`))
+		})
+		It("should keep an empty string", func() {
+			const text = `[{{ . | camelCase }}{{ . | snakeCase }}]`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "--")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`\[\]`))
+		})
+	})
+	It("should validate quote and squote methods", func() {
+		const text = `{{ . | quote }} {{ . | squote }} {{ 8080 | quote }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, `it's "ok"`)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`"it's \\"ok\\"" 'it''s "ok"' "8080"`))
+	})
+	It("should quote nil as an empty string", func() {
+		const text = `{{ .Missing | quote }} {{ .Missing | squote }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"Missing": nil})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`"" ''`))
+	})
+	Context("when validating repeat, substr and truncate methods", func() {
+		It("should count characters", func() {
+			const text = `{{ "ab" | repeat 3 }} {{ . | substr 1 3 }} {{ . | substr 2 -1 }} {{ . | truncate 2 }} {{ . | truncate 10 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "héllo")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`ababab él llo hé héllo`))
+		})
+		It("should catch an invalid range", func() {
+			const text = `{{ . | substr 4 2 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "goflat")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`invalid range [4:2]`))
+		})
+		It("should catch a negative count", func() {
+			const text = `{{ . | repeat -1 }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "goflat")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+	It("should validate wrap method", func() {
+		const text = `{{ . | wrap 10 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "the quick brown fox jumps over\nthe extraordinarily lazy dog")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`the quick\nbrown fox\njumps over\nthe\nextraordinarily\nlazy dog`))
+	})
+	It("should validate contains, hasPrefix and hasSuffix methods", func() {
+		const text = `{{ . | contains "github" }} {{ . | hasPrefix "https" }} {{ . | hasSuffix ".git" }} {{ . | contains "gitlab" }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "https://github.com/jane/repo1")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`true true false false`))
+	})
+	It("should validate indent method", func() {
+		const text = `[{{ . | indent 2 }}]`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "a\nb")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[  a\n  b\]`))
+	})
+	It("should convert int and bool values", func() {
+		const text = `[{{ .Port | padLeft 6 }}][{{ .Count | indent 2 }}][{{ .Port | padRight 6 "0" }}][{{ .Port | truncate 2 }}]` +
+			`[{{ .Enabled | title }}][{{ .Enabled | contains "ru" }}][{{ .Port | hasPrefix "80" }}][{{ .Count | repeat 2 }}][{{ .Enabled | substr 0 1 }}]`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, map[string]interface{}{"Port": 8080, "Count": 3, "Enabled": true})
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`\[  8080\]\[  3\]\[808000\]\[80\]\[True\]\[true\]\[true\]\[33\]\[t\]`))
+	})
+	Context("when validating padLeft and padRight methods", func() {
+		It("should pad to the width", func() {
+			const text = `[{{ . | padLeft 5 }}][{{ . | padRight 5 }}][{{ . | padLeft 5 "0" }}][{{ . | padRight 6 "-=" }}][{{ . | padLeft 1 }}]`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "42")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`\[   42\]\[42   \]\[00042\]\[42-=-=\]\[42\]`))
+		})
+		It("should catch an empty pad", func() {
+			const text = `{{ . | padLeft 5 "" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "42")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`empty pad`))
+		})
+	})
+})
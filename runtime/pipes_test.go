@@ -2,6 +2,7 @@ package runtime_test
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"strings"
 	"text/template"
@@ -98,6 +99,40 @@ var _ = Describe("Pipes", func() {
 				Expect(err).To(BeNil())
 				Eventually(buffer).Should(gbytes.Say("Jabber.John Chatter.Cherry"))
 			})
+			It("should accept maps and non-string fields", func() {
+				const text = `{{ . | map "Name,Port" ":" | join "," }}`
+				tmpl, err := tmpl.Parse(text)
+				Expect(err).To(BeNil())
+				err = tmpl.Execute(buffer, []interface{}{
+					map[string]interface{}{"Name": "web", "Port": 8080.0},
+					&struct {
+						Name string
+						Port int
+					}{"db", 5432},
+				})
+				Expect(err).To(BeNil())
+				Eventually(buffer).Should(gbytes.Say("web:8080,db:5432"))
+			})
+			It("should catch a missing field", func() {
+				const text = `{{ . | map "Age" ":" }}`
+				tmpl, err := tmpl.Parse(text)
+				Expect(err).To(BeNil())
+				err = tmpl.Execute(buffer, t)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(`has no field "Age"`))
+			})
+		})
+		It("should coerce the piped values of joins, split and toUpper methods", func() {
+			const text = `{{ .List | join "," }} {{ .Port | split "0" | join "-" }} {{ .Bool | toUpper }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, map[string]interface{}{
+				"List": []interface{}{"a", 1, 2.5, true},
+				"Port": 8080,
+				"Bool": false,
+			})
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`a,1,2.5,true 8-8- FALSE`))
 		})
 		It("should validate replace method", func() {
 			const text = `{{ . | replace "A" "D" }}`
@@ -132,4 +167,156 @@ var _ = Describe("Pipes", func() {
 			Eventually(buffer).Should(gbytes.Say(`baba`))
 		})
 	})
+
+	Describe("when describing pipes", func() {
+		It("should describe every default pipe", func() {
+			for _, v := range pipes.List() {
+				Expect(v.Source).To(Equal(SourceDefault))
+				Expect(v.Signature).To(HavePrefix("func("), v.Name)
+				Expect(v.Description).ToNot(BeEmpty(), v.Name)
+				Expect(v.Examples).ToNot(BeEmpty(), v.Name)
+			}
+		})
+		It("should list the pipes sorted by name", func() {
+			list := pipes.List()
+			Expect(len(list)).To(Equal(len(pipes.Map)))
+			for k := 1; k < len(list); k++ {
+				Expect(list[k-1].Name < list[k].Name).To(BeTrue())
+			}
+		})
+		It("should describe custom pipes", func() {
+			pipes.Extend(template.FuncMap{
+				"shout": func(s string) (string, error) { return s + "!", nil },
+			})
+			err := pipes.Describe(map[string]PipeInfo{
+				"shout": {Description: "Shouts a value.", Deprecated: "use toUpper"},
+			})
+			Expect(err).To(BeNil())
+			Expect(pipes.Info["shout"]).To(Equal(PipeInfo{
+				Name:        "shout",
+				Signature:   "func(string) (string, error)",
+				Description: "Shouts a value.",
+				Deprecated:  "use toUpper",
+				Source:      SourceCustom,
+			}))
+		})
+		It("should describe custom pipes with a plain map", func() {
+			pipes.Extend(template.FuncMap{
+				"shout": func(s string) (string, error) { return s + "!", nil },
+			})
+			err := pipes.DescribeCustom(map[string]struct {
+				Description string
+				Examples    []string
+				Deprecated  string
+			}{
+				"shout": {Description: "Shouts a value.", Examples: []string{`{{.Name | shout}}`}},
+			})
+			Expect(err).To(BeNil())
+			Expect(pipes.Info["shout"].Description).To(Equal("Shouts a value."))
+			Expect(pipes.Info["shout"].Examples).To(Equal([]string{`{{.Name | shout}}`}))
+			err = pipes.DescribeCustom(map[string]struct {
+				Description string
+				Examples    []string
+				Deprecated  string
+			}{"INVALID": {}})
+			Expect(err).ToNot(BeNil())
+		})
+		It("should catch describing an undefined pipe", func() {
+			err := pipes.Describe(map[string]PipeInfo{"INVALID": {}})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("INVALID"))
+		})
+		It("should print the pipes as text", func() {
+			err := pipes.Print(buffer, "text")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`join func\(string, interface \{\}\) \(string, error\) \[default\]\n    Joins`))
+		})
+		It("should print the pipes as json", func() {
+			err := pipes.Print(buffer, "json")
+			Expect(err).To(BeNil())
+			var list []PipeInfo
+			Expect(json.Unmarshal(buffer.Contents(), &list)).To(Succeed())
+			Expect(list).To(Equal(pipes.List()))
+		})
+		It("should catch an unknown format", func() {
+			err := pipes.Print(buffer, "xml")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("when overriding default pipes", func() {
+		var fm template.FuncMap
+		BeforeEach(func() {
+			fm = template.FuncMap{
+				"toUpper": func(s string) (string, error) { return s + "!", nil },
+				"shout":   func(s string) (string, error) { return s + "!", nil },
+			}
+		})
+		It("should allow the overrides", func() {
+			warnings, err := pipes.Override([]Provider{{Name: "pipes.go:CustomPipes", Pipes: fm}}, nil, OverrideAllow)
+			Expect(err).To(BeNil())
+			Expect(warnings).To(BeEmpty())
+			Expect(pipes.Overridden()).To(Equal([]string{"toUpper"}))
+			Expect(pipes.Info["shout"].Overrides).To(BeFalse())
+		})
+		It("should warn about the undeclared overrides", func() {
+			warnings, err := pipes.Override([]Provider{{Name: "pipes.go:CustomPipes", Pipes: fm}}, nil, OverrideWarn)
+			Expect(err).To(BeNil())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring(`"toUpper"`))
+
+			tmpl, err := tmpl.Funcs(pipes.Map).Parse(`{{ . | toUpper }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "a")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`a!`))
+		})
+		It("should fail on the undeclared overrides", func() {
+			_, err := pipes.Override([]Provider{{Name: "pipes.go:CustomPipes", Pipes: fm}}, nil, OverrideError)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`"toUpper"`))
+			Expect(pipes.Info["toUpper"].Source).To(Equal(SourceDefault))
+		})
+		It("should accept the declared overrides", func() {
+			warnings, err := pipes.Override([]Provider{{Name: "pipes.go:CustomPipes", Pipes: fm}}, []string{"toUpper"}, OverrideError)
+			Expect(err).To(BeNil())
+			Expect(warnings).To(BeEmpty())
+			Expect(pipes.Overridden()).To(Equal([]string{"toUpper"}))
+		})
+		It("should catch the stale declarations", func() {
+			_, err := pipes.Override([]Provider{{Name: "pipes.go:CustomPipes", Pipes: fm}}, []string{"toUpper", "shout", "toLower"}, OverrideError)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`"shout" is declared as an override but there is no default pipe`))
+			Expect(err.Error()).To(ContainSubstring(`"toLower" is declared as an override but is not defined`))
+		})
+		It("should merge the providers in order", func() {
+			warnings, err := pipes.Override([]Provider{
+				{Name: "pipes.go:CustomPipes", Pipes: fm},
+				{Name: "more.go:MorePipes", Pipes: template.FuncMap{
+					"shout": func(s string) (string, error) { return s + "!!!", nil },
+				}},
+			}, []string{"toUpper"}, OverrideWarn)
+			Expect(err).To(BeNil())
+			Expect(warnings).To(Equal([]string{`pipe "shout" of more.go:MorePipes replaces the one of pipes.go:CustomPipes`}))
+			Expect(pipes.Info["shout"].Provider).To(Equal("more.go:MorePipes"))
+			Expect(pipes.Info["toUpper"].Provider).To(Equal("pipes.go:CustomPipes"))
+
+			tmpl, err := tmpl.Funcs(pipes.Map).Parse(`{{ . | shout }}`)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "a")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`a!!!`))
+		})
+		It("should report the overrides of Extend", func() {
+			pipes.Extend(fm)
+			Expect(pipes.Overridden()).To(Equal([]string{"toUpper"}))
+			err := pipes.Print(buffer, "text")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`toUpper func\(string\) \(string, error\) \[custom, overrides default\]`))
+		})
+		It("should catch an unknown policy", func() {
+			_, err := pipes.Override([]Provider{{Name: "pipes.go:CustomPipes", Pipes: fm}}, nil, "INVALID")
+			Expect(err).ToNot(BeNil())
+		})
+	})
 })
@@ -0,0 +1,94 @@
+package runtime
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+//uuidNamespaces are the predefined namespaces of RFC 4122 for uuidv5
+var uuidNamespaces = map[string]string{
+	"dns":  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	"url":  "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+	"oid":  "6ba7b812-9dad-11d1-80b4-00c04fd430c8",
+	"x500": "6ba7b814-9dad-11d1-80b4-00c04fd430c8",
+}
+
+//shortIdEncoding is the lowercase Crockford base32 alphabet without the ambiguous i, l, o and u
+var shortIdEncoding = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+
+const shortIdLen = 12
+
+//uuidPipes are helper functions for random and deterministic identifiers,
+//uuidv5 and shortId return the same value for the same input on every render
+func uuidPipes() template.FuncMap {
+	return template.FuncMap{
+		"uuidv4": func() (string, error) {
+			var u [16]byte
+			if _, err := rand.Read(u[:]); err != nil {
+				return "", err
+			}
+			return formatUUID(u, 4), nil
+		},
+		//e.g. uuidv5 "dns" "python.org"  => "886313e1-3b8a-5372-9b90-0c9aee199e5d",
+		//the namespace is dns, url, oid, x500 or an UUID
+		"uuidv5": func(namespace, name string) (string, error) {
+			ns, err := parseUUID(namespace)
+			if err != nil {
+				return "", err
+			}
+			h := sha1.New()
+			h.Write(ns[:])
+			h.Write([]byte(name))
+			var u [16]byte
+			copy(u[:], h.Sum(nil))
+			return formatUUID(u, 5), nil
+		},
+		//e.g. shortId "repo1"  => "ht9013b24gyh"
+		"shortId": func(seed string) (string, error) {
+			sum := sha256.Sum256([]byte(seed))
+			return shortIdEncoding.EncodeToString(sum[:])[:shortIdLen], nil
+		},
+	}
+}
+
+//formatUUID sets the version and the RFC 4122 variant bits of an UUID
+func formatUUID(u [16]byte, version byte) string {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if ns, ok := uuidNamespaces[strings.ToLower(s)]; ok {
+		s = ns
+	}
+	b, err := hex.DecodeString(strings.Replace(strings.Trim(s, "{}"), "-", "", -1))
+	if err != nil || len(b) != len(u) {
+		return u, fmt.Errorf("invalid UUID namespace %q (dns, url, oid, x500 or an UUID)", s)
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+var uuidPipesInfo = map[string]PipeInfo{
+	"uuidv4": {
+		Description: "Returns a random UUID, it changes on every render.",
+		Examples:    []string{`{{uuidv4}}`},
+	},
+	"uuidv5": {
+		Description: "Returns the UUID of a name in a namespace: dns, url, oid, x500 or an UUID, it is the same on every render.",
+		Examples:    []string{`{{uuidv5 "dns" "github.com"}}`, `{{.Name | uuidv5 "6ba7b811-9dad-11d1-80b4-00c04fd430c8"}}`},
+	},
+	"shortId": {
+		Description: "Returns a 12 character id derived from a seed, it is the same on every render.",
+		Examples:    []string{`{{.Name | shortId}}`},
+	},
+}
@@ -0,0 +1,69 @@
+package runtime_test
+
+import (
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("UUID Pipes", func() {
+	var (
+		pipes  *Pipes
+		tmpl   *template.Template
+		buffer *gbytes.Buffer
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		tmpl = template.New("tester").Funcs(pipes.Map)
+		buffer = gbytes.NewBuffer()
+	})
+
+	It("should validate uuidv4 method", func() {
+		const text = `{{ uuidv4 }} {{ uuidv4 }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, nil)
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`^([0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}) [0-9a-f-]{36}$`))
+		Expect(string(buffer.Contents()[:36])).ToNot(Equal(string(buffer.Contents()[37:])))
+	})
+	Context("when validating uuidv5 method", func() {
+		It("should accept the predefined namespaces", func() {
+			const text = `{{ . | uuidv5 "dns" }} {{ "http://python.org/" | uuidv5 "URL" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "python.org")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`886313e1-3b8a-5372-9b90-0c9aee199e5d 4c565f0d-3f5a-5890-b41b-20cf47701c5e`))
+		})
+		It("should accept an UUID namespace", func() {
+			const text = `{{ . | uuidv5 "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "python.org")
+			Expect(err).To(BeNil())
+			Eventually(buffer).Should(gbytes.Say(`886313e1-3b8a-5372-9b90-0c9aee199e5d`))
+		})
+		It("should catch an invalid namespace", func() {
+			const text = `{{ . | uuidv5 "github" }}`
+			tmpl, err := tmpl.Parse(text)
+			Expect(err).To(BeNil())
+			err = tmpl.Execute(buffer, "python.org")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`invalid UUID namespace "github"`))
+		})
+	})
+	It("should validate shortId method", func() {
+		const text = `{{ . | shortId }} {{ . | shortId }} {{ "repo2" | shortId }}`
+		tmpl, err := tmpl.Parse(text)
+		Expect(err).To(BeNil())
+		err = tmpl.Execute(buffer, "repo1")
+		Expect(err).To(BeNil())
+		Eventually(buffer).Should(gbytes.Say(`^ht9013b24gyh ht9013b24gyh [0-9a-hjkmnp-tv-z]{12}$`))
+		Expect(string(buffer.Contents()[26:])).ToNot(Equal("ht9013b24gyh"))
+	})
+})
@@ -0,0 +1,150 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//Template is a file of RenderTree and the options rendering it, Name is the templatable path of its output
+//relative to the output directory and a Copy is copied unchanged
+type Template struct {
+	File       string
+	Name       string
+	Copy       bool
+	LeftDelim  string
+	RightDelim string
+	Partials   []string
+	Engine     string
+	Format     string
+	Strict     bool
+}
+
+//renderTemplate renders a template file with the partials and the layouts it extends as main does for a single
+//template, with Strict it fails on missing keys and "<no value>" and adds the names the template references to names
+func (p *Pipes) renderTemplate(t Template, data interface{}, names map[string]bool) ([]byte, error) {
+	text, err := ioutil.ReadFile(t.File)
+	if err != nil {
+		return nil, fmt.Errorf("reading template file: %s", err.Error())
+	}
+	tmpl := template.New(t.File).Delims(t.LeftDelim, t.RightDelim).Funcs(p.Map)
+	options := []string{}
+	if t.Strict {
+		options = append(options, "missingkey=error")
+		tmpl.Option(options...)
+	}
+	for _, v := range t.Partials {
+		partial, err := ioutil.ReadFile(v)
+		if err != nil {
+			return nil, fmt.Errorf("reading partial file: %s", err.Error())
+		}
+		if _, err := tmpl.New(v).Parse(string(partial)); err != nil {
+			return nil, fmt.Errorf("parsing partial file: %s", err.Error())
+		}
+	}
+	if err := ParseLayout(tmpl, t.File, string(text), t.LeftDelim, t.RightDelim); err != nil {
+		return nil, fmt.Errorf("parsing template file: %s", err.Error())
+	}
+	p.SetTemplate(tmpl)
+	p.html = nil
+
+	var output bytes.Buffer
+	if t.Engine == "html" {
+		h, err := p.HTMLTemplate(tmpl, options...)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template file: %s", err.Error())
+		}
+		err = h.Execute(&output, data)
+	} else {
+		err = tmpl.Execute(&output, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("executing template output: %s", err.Error())
+	}
+	if t.Strict {
+		if err := CheckOutput(output.Bytes()); err != nil {
+			return nil, fmt.Errorf("executing template output: %s", err.Error())
+		}
+		templateNames(tmpl, names)
+	}
+	if err := CheckFormat(t.Format, output.Bytes()); err != nil {
+		return nil, fmt.Errorf("checking template output: %s", err.Error())
+	}
+	return output.Bytes(), nil
+}
+
+//RenderTree renders the templates and copies the other files into dir with their rendered names, the file
+//pipes of a template are relative to its directory and with Strict it returns the input fields of data that
+//no template references. The names, those of the copied files included, are templates in the delimiters of
+//the directory and two files cannot render the same name. Every file is rendered before any is written so
+//that a failing template writes nothing
+func (p *Pipes) RenderTree(dir, leftDelim, rightDelim string, files []Template, data interface{}) ([]string, error) {
+	type output struct {
+		file    string
+		content []byte
+		mode    os.FileMode
+	}
+	outputs := []output{}
+	sources := make(map[string]string)
+	names := make(map[string]bool)
+	strict := false
+	for _, v := range files {
+		name, err := p.renderName(v.Name, leftDelim, rightDelim, data)
+		if err != nil {
+			return nil, err
+		}
+		if source, ok := sources[name]; ok {
+			return nil, fmt.Errorf("%s and %s both render %s", source, v.File, filepath.ToSlash(name))
+		}
+		sources[name] = v.File
+		fi, err := os.Stat(v.File)
+		if err != nil {
+			return nil, err
+		}
+		var content []byte
+		if v.Copy {
+			content, err = ioutil.ReadFile(v.File)
+		} else {
+			p.fileDir = filepath.Dir(v.File)
+			content, err = p.renderTemplate(v, data, names)
+			strict = strict || v.Strict
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", v.File, err.Error())
+		}
+		outputs = append(outputs, output{filepath.Join(dir, name), content, fi.Mode().Perm()})
+	}
+	for _, v := range outputs {
+		if err := os.MkdirAll(filepath.Dir(v.file), 0777); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(v.file, v.content, v.mode); err != nil {
+			return nil, err
+		}
+	}
+	if !strict {
+		return []string{}, nil
+	}
+	return unusedFields(names, data), nil
+}
+
+//renderName renders the output path of a file, it cannot be outside of the output directory
+func (p *Pipes) renderName(text, leftDelim, rightDelim string, data interface{}) (string, error) {
+	tmpl, err := template.New(text).Delims(leftDelim, rightDelim).Funcs(p.Map).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing file name %s: %s", text, err.Error())
+	}
+	var name bytes.Buffer
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("executing file name %s: %s", text, err.Error())
+	}
+	clean := filepath.Clean(filepath.FromSlash(name.String()))
+	if clean == "." || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file name %s renders %q outside of the output directory", text, name.String())
+	}
+	return clean, nil
+}
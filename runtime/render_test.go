@@ -0,0 +1,132 @@
+package runtime_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	type Repos struct {
+		Env    string
+		Name   string
+		Branch string
+	}
+	var (
+		pipes     *Pipes
+		srcDir    string
+		outDir    string
+		data      struct{ Repos Repos }
+		templates []Template
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		srcDir, _ = ioutil.TempDir(os.TempDir(), "")
+		outDir, _ = ioutil.TempDir(os.TempDir(), "")
+		data.Repos = Repos{Env: "staging", Name: "repo1", Branch: "master"}
+		files := map[string]string{
+			"pipeline.yml.tmpl":         "name: {{.Repos.Name}}",
+			"ci/job.json.tmpl":          `[[- /* delims "[[" "]]" */ -]]{"run": "${{ github.sha }}", "env": [[.Repos.Env | quote]]}`,
+			"ci/scripts/deploy.sh":      "echo {{ .Repos.Name }}",
+			"ci/readFile.yml.tmpl":      `{{readFile "../VERSION"}}`,
+			"VERSION":                   "1.2.3",
+			"{{.Repos.Env}}-values.yml": "replicas: 2",
+		}
+		for k, v := range files {
+			file := filepath.Join(srcDir, k)
+			Expect(os.MkdirAll(filepath.Dir(file), 0777)).To(Succeed())
+			Expect(ioutil.WriteFile(file, []byte(v), 0750)).To(Succeed())
+		}
+		pipes.SetFileRoot(srcDir, srcDir)
+		templates = []Template{
+			{File: filepath.Join(srcDir, "pipeline.yml.tmpl"), Name: "pipeline.yml", Strict: true},
+			{File: filepath.Join(srcDir, "ci/job.json.tmpl"), Name: "ci/{{.Repos.Env}}-job.json", LeftDelim: "[[", RightDelim: "]]", Format: "json", Strict: true},
+			{File: filepath.Join(srcDir, "ci/scripts/deploy.sh"), Name: "ci/scripts/deploy.sh", Copy: true},
+			{File: filepath.Join(srcDir, "ci/readFile.yml.tmpl"), Name: "ci/version.yml"},
+			{File: filepath.Join(srcDir, "{{.Repos.Env}}-values.yml"), Name: "{{.Repos.Env}}-values.yml", Copy: true},
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(srcDir)
+		os.RemoveAll(outDir)
+	})
+	read := func(name string) string {
+		data, err := ioutil.ReadFile(filepath.Join(outDir, name))
+		Expect(err).To(BeNil())
+		return string(data)
+	}
+
+	Context("when validating RenderTree method", func() {
+		It("should render the templates and copy the files with their rendered names into a mirrored directory", func() {
+			unused, err := pipes.RenderTree(outDir, "", "", templates, data)
+			Expect(err).To(BeNil())
+			Expect(read("pipeline.yml")).To(Equal("name: repo1"))
+			Expect(read("ci/staging-job.json")).To(Equal(`{"run": "${{ github.sha }}", "env": "staging"}`))
+			Expect(read("ci/scripts/deploy.sh")).To(Equal("echo {{ .Repos.Name }}"))
+			Expect(read("ci/version.yml")).To(Equal("1.2.3"))
+			Expect(read("staging-values.yml")).To(Equal("replicas: 2"))
+			fi, err := os.Stat(filepath.Join(outDir, "ci/scripts/deploy.sh"))
+			Expect(err).To(BeNil())
+			Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			Expect(unused).To(Equal([]string{"Repos.Branch"}))
+		})
+		It("should render the names in the delimiters of the directory", func() {
+			templates[4].Name = "<%.Repos.Env%>.yml"
+			_, err := pipes.RenderTree(outDir, "<%", "%>", templates, data)
+			Expect(err).To(BeNil())
+			Expect(read("staging.yml")).To(Equal("replicas: 2"))
+		})
+		It("should catch an invalid template with its file", func() {
+			templates[0].Format = "json"
+			_, err := pipes.RenderTree(outDir, "", "", templates, data)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(templates[0].File))
+			Expect(err.Error()).To(ContainSubstring("output is not valid json"))
+		})
+		It("should not write any file when a template fails", func() {
+			templates[3].Format = "json"
+			_, err := pipes.RenderTree(outDir, "", "", templates, data)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(templates[3].File))
+			files, err := ioutil.ReadDir(outDir)
+			Expect(err).To(BeNil())
+			Expect(files).To(BeEmpty())
+		})
+		It("should report the fields that no template references when a template ignores an input", func() {
+			static := filepath.Join(srcDir, "static.yml.tmpl")
+			Expect(ioutil.WriteFile(static, []byte("static: true"), 0666)).To(Succeed())
+			templates = append(templates, Template{File: static, Name: "static.yml", Strict: true})
+			unused, err := pipes.RenderTree(outDir, "", "", templates, data)
+			Expect(err).To(BeNil())
+			Expect(unused).To(Equal([]string{"Repos.Branch"}))
+		})
+		Context("when two files render the same name", func() {
+			It("should catch a copied file and a template", func() {
+				templates[2].Name = "pipeline.yml"
+				_, err := pipes.RenderTree(outDir, "", "", templates, data)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(templates[0].File + " and " + templates[2].File + " both render pipeline.yml"))
+			})
+			It("should catch two templated names", func() {
+				templates[0].Name = "ci/staging-job.json"
+				_, err := pipes.RenderTree(outDir, "", "", templates, data)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(templates[0].File + " and " + templates[1].File + " both render ci/staging-job.json"))
+				files, err := ioutil.ReadDir(outDir)
+				Expect(err).To(BeNil())
+				Expect(files).To(BeEmpty())
+			})
+		})
+		It("should catch a file name outside of the output directory", func() {
+			templates[0].Name = "../{{.Repos.Name}}.yml"
+			_, err := pipes.RenderTree(outDir, "", "", templates, data)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`renders "../repo1.yml" outside of the output directory`))
+		})
+	})
+})
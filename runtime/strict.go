@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+//noValue is the output of text/template for a missing map key without missingkey=error
+const noValue = "<no value>"
+
+//CheckOutput returns an error for the first line of a rendered output containing "<no value>"
+func CheckOutput(output []byte) error {
+	for k, v := range bytes.Split(output, []byte("\n")) {
+		if bytes.Contains(v, []byte(noValue)) {
+			return fmt.Errorf("strict: line %d renders %q: %s", k+1, noValue, strings.TrimSpace(string(v)))
+		}
+	}
+	return nil
+}
+
+//UnusedFields returns the inputs and the input fields e.g. "Private.Password" of a data struct whose names
+//are not referenced by the templates of t, a name is referenced as a field e.g. .Password or as a string
+//e.g. index .Private "Password" so the fields reached through the pipes or tpl are not detected
+func UnusedFields(t *template.Template, data interface{}) []string {
+	names := make(map[string]bool)
+	templateNames(t, names)
+	return unusedFields(names, data)
+}
+
+//templateNames adds the field names and the strings of the templates of t to names
+func templateNames(t *template.Template, names map[string]bool) {
+	for _, v := range t.Templates() {
+		if v.Tree != nil {
+			fieldNames(v.Tree.Root, names)
+		}
+	}
+}
+
+//unusedFields returns the inputs and the input fields of data whose names are not in names
+func unusedFields(names map[string]bool, data interface{}) []string {
+	unused := []string{}
+	rv := reflect.ValueOf(indirect(data))
+	if rv.Kind() != reflect.Struct {
+		return unused
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		input := rv.Type().Field(i)
+		if input.PkgPath != "" {
+			continue
+		}
+		if !names[input.Name] {
+			unused = append(unused, input.Name)
+			continue
+		}
+		for _, v := range inputFields(rv.Field(i).Interface()) {
+			if !names[v] {
+				unused = append(unused, input.Name+"."+v)
+			}
+		}
+	}
+	return unused
+}
+
+//inputFields returns the exported fields of a struct or the sorted string keys of a map
+func inputFields(v interface{}) []string {
+	fields := []string{}
+	rv := reflect.ValueOf(indirect(v))
+	switch rv.Kind() {
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.PkgPath == "" {
+				fields = append(fields, f.Name)
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		for _, k := range rv.MapKeys() {
+			fields = append(fields, k.String())
+		}
+		sort.Strings(fields)
+	}
+	return fields
+}
+
+//fieldNames adds the field names and the strings of a parse tree to names
+func fieldNames(node parse.Node, names map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Nodes {
+			fieldNames(v, names)
+		}
+	case *parse.ActionNode:
+		fieldNames(n.Pipe, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, v := range n.Cmds {
+			fieldNames(v, names)
+		}
+	case *parse.CommandNode:
+		for _, v := range n.Args {
+			fieldNames(v, names)
+		}
+	case *parse.FieldNode:
+		for _, v := range n.Ident {
+			names[v] = true
+		}
+	case *parse.VariableNode:
+		for _, v := range n.Ident[1:] {
+			names[v] = true
+		}
+	case *parse.ChainNode:
+		fieldNames(n.Node, names)
+		for _, v := range n.Field {
+			names[v] = true
+		}
+	case *parse.StringNode:
+		names[n.Text] = true
+	case *parse.IfNode:
+		fieldNames(&n.BranchNode, names)
+	case *parse.RangeNode:
+		fieldNames(&n.BranchNode, names)
+	case *parse.WithNode:
+		fieldNames(&n.BranchNode, names)
+	case *parse.BranchNode:
+		fieldNames(n.Pipe, names)
+		fieldNames(n.List, names)
+		fieldNames(n.ElseList, names)
+	case *parse.TemplateNode:
+		fieldNames(n.Pipe, names)
+	}
+}
@@ -0,0 +1,60 @@
+package runtime_test
+
+import (
+	"bytes"
+	"text/template"
+
+	. "github.com/aminjam/goflat/runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Strict", func() {
+	type Repos struct {
+		Name   string
+		Branch string
+	}
+	var (
+		pipes *Pipes
+		data  struct {
+			Repos   Repos
+			Private map[string]string
+			Stale   []string
+		}
+	)
+	BeforeEach(func() {
+		pipes = NewPipes()
+		data.Repos = Repos{Name: "repo1", Branch: "master"}
+		data.Private = map[string]string{"Password": "secret", "Token": "abc"}
+	})
+	parse := func(text string) *template.Template {
+		tmpl, err := template.New("tester").Funcs(pipes.Map).Parse(text)
+		Expect(err).To(BeNil())
+		return tmpl
+	}
+
+	Context("when validating CheckOutput method", func() {
+		It("should accept an output without missing values", func() {
+			Expect(CheckOutput([]byte("name: repo1\n"))).To(BeNil())
+		})
+		It("should catch a missing value", func() {
+			var output bytes.Buffer
+			tmpl := parse("name: {{.Repos.Name}}\npassword: {{.Private.Pasword}}\n")
+			Expect(tmpl.Execute(&output, data)).To(Succeed())
+			err := CheckOutput(output.Bytes())
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`line 2 renders "<no value>": password: <no value>`))
+		})
+	})
+	Context("when validating UnusedFields method", func() {
+		It("should report the inputs and the fields that are not referenced", func() {
+			tmpl := parse(`{{define "repo"}}{{.Name}}{{end}}{{with .Repos}}{{template "repo" .}}{{end}} {{index .Private "Token"}}`)
+			Expect(UnusedFields(tmpl, data)).To(Equal([]string{"Repos.Branch", "Private.Password", "Stale"}))
+		})
+		It("should find the fields of variables, chains and branches", func() {
+			tmpl := parse(`{{$r := .Repos}}{{if $.Private.Password}}{{$r.Name}}{{else}}{{range .Stale}}{{end}}{{end}}{{(.Private).Token}}{{.Repos.Name | toUpper}}{{.Repos.Branch}}`)
+			Expect(UnusedFields(tmpl, data)).To(BeEmpty())
+		})
+	})
+})
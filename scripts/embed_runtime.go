@@ -1,7 +1,6 @@
 package main
 
 import (
-	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -10,21 +9,29 @@ import (
 
 func main() {
 	fs, _ := ioutil.ReadDir("runtime")
-	runtime_files := []string{"main.gotempl", "pipes.go"}
+	pipes := []string{}
 
 	out, _ := os.Create("runtime.go")
 	out.Write([]byte("package goflat\n\nconst (\n"))
 	for _, f := range fs {
-		for _, runtime_file := range runtime_files {
-			if runtime_file == f.Name() {
-				varName := strings.Replace(strings.Title(f.Name()), ".", "", -1)
-				fPath := filepath.Join("runtime", f.Name())
-				out.Write([]byte(varName + " = `"))
-				f, _ := os.Open(fPath)
-				io.Copy(out, f)
-				out.Write([]byte("`\n"))
-			}
+		//main.gotempl and every non-test go file in runtime are embedded
+		if f.Name() != "main.gotempl" &&
+			(filepath.Ext(f.Name()) != ".go" || strings.HasSuffix(f.Name(), "_test.go")) {
+			continue
 		}
+		varName := strings.Title(strings.Replace(f.Name(), "_", " ", -1))
+		varName = strings.Replace(strings.Replace(varName, ".", "", -1), " ", "", -1)
+		if filepath.Ext(f.Name()) == ".go" {
+			pipes = append(pipes, varName)
+		}
+		fPath := filepath.Join("runtime", f.Name())
+		out.Write([]byte(varName + " = `"))
+		content, _ := ioutil.ReadFile(fPath)
+		//backquotes cannot be part of a raw string literal
+		out.Write([]byte(strings.Replace(string(content), "`", "` + \"`\" + `", -1)))
+		out.Write([]byte("`\n"))
 	}
-	out.Write([]byte(")\n"))
+	out.Write([]byte(")\n\n"))
+	out.Write([]byte("//RuntimePipes is the list of embedded runtime files that define the default pipes\n"))
+	out.Write([]byte("var RuntimePipes = []string{" + strings.Join(pipes, ", ") + "}\n"))
 }